@@ -0,0 +1,37 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/tamalsaha/learn-chi/binding/defaults"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// JSON provides obj, decoded from the JSON request body, to the handlers of a router. The
+// defaults of obj are applied using the defaults package, then structs are validated with the
+// validator using their validate tags.
+//
+// Like Params, the body is only decoded when a handler asks for it. Decode failures are written
+// as metav1.Status with a cause that tells empty, truncated and malformed bodies apart, including
+// the line and column of the error, and values of the wrong type by their JSON field path.
+// Unknown fields are handled according to the WithFieldValidation level of the route.
+//
+// JSON ignores the Content-Type of requests, use Bind to accept other media types as well.
+func JSON(obj interface{}) func(next http.Handler) http.Handler {
+	ensureNotPointer(obj)
+	typ := reflect.TypeOf(obj)
+	return bindProvider(typ, fmt.Sprintf("binding.JSON(%s)", typ), func(w http.ResponseWriter, r *http.Request, ptr interface{}) error {
+		return bindBody(w, r, ptr, jsonDecoder{})
+	})
+}
+
+// applyDefaults sets the defaults of the object ptr points to. Invalid default tags are bugs
+// in the source code and result in 500 Internal Server Error.
+func applyDefaults(ptr interface{}) error {
+	if err := defaults.Apply(ptr); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"go.wandrs.dev/inject"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// maxBodySize is the maximum size of request bodies of a route set by WithMaxBodySize.
+type maxBodySize int64
+
+var maxBodySizeType = reflect.TypeOf(maxBodySize(0))
+
+// WithMaxBodySize limits the size of the request bodies decoded by JSON, Bind, Form and JSONStream
+// on the routes it is registered on to n bytes, eg. r.With(binding.WithMaxBodySize(10 << 20), binding.JSONStream(User{})).
+// Larger bodies fail with 413 Request Entity Too Large. Bodies are unlimited by default. The MaxSize
+// of WithMultipartLimits takes precedence for forms.
+func WithMaxBodySize(n int64) func(next http.Handler) http.Handler {
+	if n <= 0 {
+		panic(fmt.Sprintf("binding: max body size must be positive, found %d", n))
+	}
+
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			injector.Map(maxBodySize(n))
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: fmt.Sprintf("binding.WithMaxBodySize(%d)", n), provides: []reflect.Type{maxBodySizeType}})
+}
+
+// maxBodySizeOf returns the maximum size of the request body of the route of r, 0 if it is unlimited.
+func maxBodySizeOf(r *http.Request) int64 {
+	if injector, _ := r.Context().Value(injectorKey{}).(inject.Injector); injector != nil {
+		if v := injector.GetVal(maxBodySizeType); v.IsValid() {
+			return int64(v.Interface().(maxBodySize))
+		}
+	}
+	return 0
+}
+
+// limitBody limits the body of r to n bytes, if n is positive. Reading more fails with errBodyTooLarge.
+func limitBody(r *http.Request, n int64) {
+	if n > 0 && r.Body != nil {
+		r.Body = &maxBytesReader{r: r.Body, n: n}
+	}
+}
+
+// bodyTooLarge returns the 413 Request Entity Too Large error of a body larger than n bytes.
+func bodyTooLarge(n int64) error {
+	return apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("request body exceeds %d bytes", n))
+}
+
+var errBodyTooLarge = errors.New("binding: request body too large")
+
+// maxBytesReader is like http.MaxBytesReader, but returns errBodyTooLarge, so that the
+// error can be told apart from other errors reading the body.
+type maxBytesReader struct {
+	r io.ReadCloser
+	n int64 // bytes left
+}
+
+func (l *maxBytesReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1] // read one byte more to detect bodies that exceed the limit
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n - 1, errBodyTooLarge
+	}
+	return n, err
+}
+
+func (l *maxBytesReader) Close() error {
+	return l.r.Close()
+}
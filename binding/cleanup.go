@@ -0,0 +1,120 @@
+package binding
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"go.wandrs.dev/inject"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// requestInjector is the injector of a single request. It records the values mapped during
+// the request that implement io.Closer and the cleanup funcs registered with AddCleanup,
+// so that they are released when the request completes.
+type requestInjector struct {
+	inject.Injector
+	cleanups []func() error
+	closers  []io.Closer
+}
+
+var _ inject.Injector = &requestInjector{}
+
+func newRequestInjector() *requestInjector {
+	return &requestInjector{Injector: inject.New()}
+}
+
+func (i *requestInjector) Map(val interface{}) inject.TypeMapper {
+	i.Injector.Map(val)
+	i.track(val)
+	return i
+}
+
+func (i *requestInjector) MapTo(val interface{}, ifacePtr interface{}) inject.TypeMapper {
+	i.Injector.MapTo(val, ifacePtr)
+	i.track(val)
+	return i
+}
+
+func (i *requestInjector) Set(typ reflect.Type, val reflect.Value) inject.TypeMapper {
+	i.Injector.Set(typ, val)
+	if val.IsValid() && val.CanInterface() {
+		i.track(val.Interface())
+	}
+	return i
+}
+
+func (i *requestInjector) track(val interface{}) {
+	c, ok := val.(io.Closer)
+	if !ok {
+		return
+	}
+	if v := reflect.ValueOf(c); canDeref(v) && v.IsNil() {
+		return
+	}
+	if reflect.TypeOf(c).Comparable() {
+		for _, tracked := range i.closers {
+			if tracked == c {
+				return // mapped more than once, eg. using Map and MapTo
+			}
+		}
+	}
+	i.cleanups = append(i.cleanups, c.Close)
+	i.closers = append(i.closers, c)
+}
+
+// cleanup runs the cleanup funcs in reverse order. Errors and panics are reported
+// using runtime.HandleError, so that every cleanup func gets a chance to run.
+func (i *requestInjector) cleanup() {
+	for n := len(i.cleanups) - 1; n >= 0; n-- {
+		runCleanup(i.cleanups[n])
+	}
+	for n := range i.cleanups {
+		i.cleanups[n] = nil
+	}
+	i.cleanups = i.cleanups[:0]
+	for n := range i.closers {
+		i.closers[n] = nil
+	}
+	i.closers = i.closers[:0]
+}
+
+func runCleanup(fn func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtime.HandleError(fmt.Errorf("binding: cleanup panicked: %v", r))
+		}
+	}()
+	if err := fn(); err != nil {
+		runtime.HandleError(fmt.Errorf("binding: cleanup failed: %w", err))
+	}
+}
+
+// release cleans up the injector and drops all mapped values and the parent scope,
+// so that nothing leaks into the next request served by the injector.
+func (i *requestInjector) release() {
+	i.cleanup()
+	// Reset also resets the parent, which would wipe the scope shared with other requests
+	i.Injector.SetParent(nil)
+	i.Injector.Reset()
+}
+
+// untracked returns the injector without cleanup tracking. It is used to map values that
+// outlive the request, eg. values mapped by Map middlewares or singletons.
+func untracked(injector inject.Injector) inject.Injector {
+	if ri, ok := injector.(*requestInjector); ok {
+		return ri.Injector
+	}
+	return injector
+}
+
+// AddCleanup registers fn to be called when the request completes, even if the handler panics.
+// Cleanup funcs and mapped io.Closers are called in the reverse order they were registered.
+func AddCleanup(r *http.Request, fn func() error) {
+	injector, _ := r.Context().Value(injectorKey{}).(*requestInjector)
+	if injector == nil {
+		panic("chi: register Injector middleware")
+	}
+	injector.cleanups = append(injector.cleanups, fn)
+}
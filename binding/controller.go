@@ -0,0 +1,154 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-chi/chi/v5"
+	"go.wandrs.dev/inject"
+)
+
+// controllerMethods maps the methods of a controller to REST routes. Methods that are not
+// implemented by a controller are not routed.
+var controllerMethods = []struct {
+	name    string
+	method  string
+	pattern string
+}{
+	{"List", http.MethodGet, "/"},
+	{"Create", http.MethodPost, "/"},
+	{"Get", http.MethodGet, "/{name}"},
+	{"Update", http.MethodPut, "/{name}"},
+	{"Patch", http.MethodPatch, "/{name}"},
+	{"Delete", http.MethodDelete, "/{name}"},
+}
+
+// injectField is a field of a controller that is set from the request injector.
+type injectField struct {
+	index int
+	name  string
+	typ   reflect.Type
+}
+
+// Controller returns a func that routes the methods of ctrl on a chi router, eg.
+//
+//	r.Route("/nodes", binding.Controller(&NodeController{}))
+//
+// ctrl must be a pointer to a struct. The methods List, Create, Get, Update, Patch and Delete
+// are routed to GET /, POST /, GET /{name}, PUT /{name}, PATCH /{name} and DELETE /{name}.
+// They are handlers like the ones passed to Handler: their arguments are resolved from the
+// request injector and their return values are written to the response.
+//
+// Every request is served by a copy of ctrl whose fields tagged `inject:""` are set from the
+// request injector and its providers, like inject.Applicator.Apply does. So controllers keep
+// their dependencies in fields instead of globals, and fields that are not tagged can hold
+// configuration shared by all requests.
+func Controller(ctrl interface{}) func(r chi.Router) {
+	typ := reflect.TypeOf(ctrl)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("binding: controller must be a pointer to a struct, found %T", ctrl))
+	}
+	proto := reflect.ValueOf(ctrl).Elem()
+
+	fields, err := injectFields(typ.Elem())
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+
+	type route struct {
+		method  string
+		pattern string
+		handler http.HandlerFunc
+	}
+	var routes []route
+	for _, cm := range controllerMethods {
+		m, ok := typ.MethodByName(cm.name)
+		if !ok {
+			continue
+		}
+		routes = append(routes, route{
+			method:  cm.method,
+			pattern: cm.pattern,
+			handler: controllerHandler(typ, proto, fields, m),
+		})
+	}
+	if len(routes) == 0 {
+		panic(fmt.Sprintf("binding: controller %s has none of the methods List, Create, Get, Update, Patch or Delete", typ))
+	}
+
+	return func(r chi.Router) {
+		for _, route := range routes {
+			r.Method(route.method, route.pattern, route.handler)
+		}
+	}
+}
+
+// injectFields returns the fields of the struct type typ tagged with inject.
+func injectFields(typ reflect.Type) ([]injectField, error) {
+	var fields []injectField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if _, ok := f.Tag.Lookup("inject"); !ok && f.Tag != "inject" {
+			continue
+		}
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("field %s.%s tagged with inject must be exported", typ, f.Name)
+		}
+		fields = append(fields, injectField{index: i, name: f.Name, typ: f.Type})
+	}
+	return fields, nil
+}
+
+func controllerHandler(typ reflect.Type, proto reflect.Value, fields []injectField, m reflect.Method) http.HandlerFunc {
+	name := fmt.Sprintf("(%s).%s", typ, m.Name)
+
+	shape, err := newReturnShape(m.Type)
+	if err != nil {
+		panic(fmt.Sprintf("binding: handler %s %v", name, err))
+	}
+
+	requires := make([]reflect.Type, 0, len(fields)+m.Type.NumIn()-1)
+	for _, f := range fields {
+		requires = append(requires, f.typ)
+	}
+	for i := 1; i < m.Type.NumIn(); i++ { // skip the receiver
+		requires = append(requires, m.Type.In(i))
+	}
+
+	return declareHandler(func(w http.ResponseWriter, r *http.Request) {
+		injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+		if injector == nil {
+			panic("chi: register Injector middleware")
+		}
+
+		ctrl := reflect.New(typ.Elem())
+		ctrl.Elem().Set(proto)
+		if err := applyFields(injector, ctrl.Elem(), fields); err != nil {
+			handleResolveError(err, w, r)
+			return
+		}
+
+		results, ok := invoke(injector, ctrl.Method(m.Index).Interface(), w, r)
+		if !ok {
+			return
+		}
+		shape.write(w, r, results)
+	}, declaration{name: name, requires: requires})
+}
+
+// applyFields sets the inject fields of the struct v from the injector and its providers.
+func applyFields(injector inject.Injector, v reflect.Value, fields []injectField) error {
+	ps, scope := providersOf(injector), scopeOf(injector)
+	for _, f := range fields {
+		val, err := resolveType(injector, ps, scope, f.typ, nil)
+		if err != nil {
+			return err
+		}
+		if !val.IsValid() {
+			return fmt.Errorf("binding: value not found for type %s required by field %s.%s", f.typ, v.Type(), f.name)
+		}
+		v.Field(f.index).Set(val)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package binding
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Decoder decodes request bodies of a media type for Bind.
+type Decoder interface {
+	// Decode decodes body into the object ptr points to. Empty bodies are rejected before
+	// Decode is called. Errors are converted into metav1.Status by NewBindingError, so
+	// decoders should return *bindingerrors.SyntaxError for malformed bodies.
+	Decode(body []byte, ptr interface{}) error
+}
+
+// UnknownFieldsDecoder is implemented by decoders that can report the fields of a body that
+// don't exist in the object it is decoded into, eg. addresses[0].zip. Unknown fields of bodies
+// decoded by other decoders are ignored regardless of the WithFieldValidation level of the route.
+type UnknownFieldsDecoder interface {
+	Decoder
+	UnknownFields(body []byte, ptr interface{}) ([]string, error)
+}
+
+// DecoderFunc adapts an unmarshal func to a Decoder, eg. binding.DecoderFunc(toml.Unmarshal).
+type DecoderFunc func(body []byte, ptr interface{}) error
+
+func (fn DecoderFunc) Decode(body []byte, ptr interface{}) error {
+	return fn(body, ptr)
+}
+
+const (
+	mediaTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	mediaTypeMultipartForm  = "multipart/form-data"
+)
+
+var decoders = struct {
+	sync.RWMutex
+	m map[string]Decoder
+}{m: map[string]Decoder{
+	"application/json":   jsonDecoder{},
+	"application/yaml":   yamlDecoder{},
+	"application/x-yaml": yamlDecoder{},
+	"text/yaml":          yamlDecoder{},
+	"application/xml":    xmlDecoder{},
+	"text/xml":           xmlDecoder{},
+}}
+
+// RegisterDecoder registers d as the decoder of request bodies of mediaType for Bind, eg.
+//
+//	binding.RegisterDecoder("application/toml", binding.DecoderFunc(toml.Unmarshal))
+//
+// JSON, YAML and XML are registered by default, CBOR and MessagePack by the Register funcs of the
+// packages in binding/decoders. Media types with a structured syntax suffix, eg.
+// application/vnd.api+json, are decoded by the decoder of the suffix unless they are registered
+// themselves. Decoders may be registered while requests are served, registering a decoder for a
+// media type again replaces it.
+func RegisterDecoder(mediaType string, d Decoder) {
+	decoders.Lock()
+	defer decoders.Unlock()
+	decoders.m[strings.ToLower(mediaType)] = d
+}
+
+// decoderFor returns the decoder of mediaType.
+func decoderFor(mediaType string) (Decoder, bool) {
+	decoders.RLock()
+	defer decoders.RUnlock()
+	if d, ok := decoders.m[mediaType]; ok {
+		return d, true
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		d, ok := decoders.m["application/"+mediaType[i+1:]]
+		return d, ok
+	}
+	return nil, false
+}
+
+// decoderMediaTypes returns the media types of the registered decoders, sorted.
+func decoderMediaTypes() []string {
+	decoders.RLock()
+	defer decoders.RUnlock()
+	types := make([]string, 0, len(decoders.m))
+	for t := range decoders.m {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Bind provides obj to the handlers of a router, decoded from the request body according to its
+// Content-Type: forms and multipart forms like Form does, and other media types using the
+// Decoder registered for them with RegisterDecoder. Other media types fail with 415 Unsupported
+// Media Type.
+//
+// Whatever the media type, the defaults of obj are applied, it is validated and errors are
+// written as metav1.Status, like JSON does. Fields of YAML bodies are named by their json tags,
+// as the YAML is converted into JSON first, like the kube-apiserver does.
+func Bind(obj interface{}) func(next http.Handler) http.Handler {
+	ensureNotPointer(obj)
+	typ := reflect.TypeOf(obj)
+	var files []formFile
+	if typ.Kind() == reflect.Struct {
+		files = formFiles(typ)
+	}
+
+	return bindProvider(typ, fmt.Sprintf("binding.Bind(%s)", typ), func(w http.ResponseWriter, r *http.Request, ptr interface{}) error {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == mediaTypeFormURLEncoded || mediaType == mediaTypeMultipartForm {
+			return bindForm(w, r, ptr, files)
+		}
+		if d, ok := decoderFor(mediaType); ok {
+			return bindBody(w, r, ptr, d)
+		}
+		return unsupportedMediaType(mediaType, append([]string{mediaTypeFormURLEncoded, mediaTypeMultipartForm}, decoderMediaTypes()...))
+	})
+}
+
+// bindBody decodes the body of r into ptr using d and validates it.
+func bindBody(w http.ResponseWriter, r *http.Request, ptr interface{}, d Decoder) error {
+	limit := maxBodySizeOf(r)
+	limitBody(r, limit)
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			if err == errBodyTooLarge {
+				return bodyTooLarge(limit)
+			}
+			return err
+		}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return io.EOF // converted into an empty body cause by NewBindingError
+	}
+	if err := d.Decode(body, ptr); err != nil {
+		return err
+	}
+	if ud, ok := d.(UnknownFieldsDecoder); ok {
+		err := checkUnknownFields(w, r, func() ([]string, error) {
+			return ud.UnknownFields(body, ptr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := applyDefaults(ptr); err != nil {
+		return err
+	}
+	return validateRequest(r, ptr)
+}
+
+// unsupportedMediaType returns the 415 Unsupported Media Type error of a request body.
+func unsupportedMediaType(mediaType string, supported []string) error {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusUnsupportedMediaType,
+		Reason:  metav1.StatusReasonUnsupportedMediaType,
+		Message: fmt.Sprintf("unsupported media type %q, expected %s", mediaType, strings.Join(supported, ", ")),
+	}}
+}
+
+// jsonDecoder decodes JSON bodies, reporting errors with their position.
+type jsonDecoder struct{}
+
+func (jsonDecoder) Decode(body []byte, ptr interface{}) error {
+	return bindingerrors.NewJSONError(json.Unmarshal(body, ptr), body)
+}
+
+func (jsonDecoder) UnknownFields(body []byte, ptr interface{}) ([]string, error) {
+	return bindingerrors.UnknownJSONFields(body, ptr)
+}
+
+// yamlDecoder decodes YAML bodies by converting them into JSON, so that objects with json tags,
+// eg. k8s API types, can be decoded from YAML.
+type yamlDecoder struct{}
+
+// yamlLineError matches the errors of gopkg.in/yaml.v2 with a line, eg. yaml: line 3: mapping
+// values are not allowed in this context.
+var yamlLineError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func (yamlDecoder) toJSON(body []byte) ([]byte, error) {
+	data, err := yaml.YAMLToJSON(body)
+	if err != nil {
+		e := &bindingerrors.SyntaxError{Format: "YAML", Err: errors.New(strings.TrimPrefix(err.Error(), "yaml: "))}
+		if m := yamlLineError.FindStringSubmatch(err.Error()); m != nil {
+			e.Line, _ = strconv.Atoi(m[1])
+			e.Err = errors.New(m[2])
+		}
+		return nil, e
+	}
+	return data, nil
+}
+
+func (d yamlDecoder) Decode(body []byte, ptr interface{}) error {
+	data, err := d.toJSON(body)
+	if err != nil {
+		return err
+	}
+	// type errors keep the field path of the value, their position is the one in the JSON
+	return json.Unmarshal(data, ptr)
+}
+
+func (d yamlDecoder) UnknownFields(body []byte, ptr interface{}) ([]string, error) {
+	data, err := d.toJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	return bindingerrors.UnknownJSONFields(data, ptr)
+}
+
+// xmlDecoder decodes XML bodies using encoding/xml and the xml tags of fields.
+type xmlDecoder struct{}
+
+func (xmlDecoder) Decode(body []byte, ptr interface{}) error {
+	err := xml.Unmarshal(body, ptr)
+	if se, ok := err.(*xml.SyntaxError); ok {
+		if se.Msg == "unexpected EOF" {
+			return io.ErrUnexpectedEOF // converted into a truncated body cause by NewBindingError
+		}
+		return &bindingerrors.SyntaxError{Format: "XML", Err: errors.New(se.Msg), Line: se.Line}
+	}
+	return err
+}
@@ -0,0 +1,343 @@
+// Package cbor decodes CBOR (RFC 8949) request bodies for binding.Bind. Bodies are converted
+// into JSON first, like YAML ones, so fields are named by their json tags and unknown fields are
+// reported like the ones of JSON bodies. Register it once, before the routes are served:
+//
+//	cbor.Register()
+//
+// Byte strings are decoded into []byte fields, epoch-based date/time (tag 1) into time.Time
+// fields and bignums (tags 2 and 3) into numbers. Other tags are ignored. NaN and infinite
+// floats can't be represented in JSON and are rejected.
+package cbor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tamalsaha/learn-chi/binding"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+)
+
+// MediaType is the media type of CBOR bodies.
+const MediaType = "application/cbor"
+
+// maxDepth is the maximum nesting of arrays, maps and tags of a body.
+const maxDepth = 1000
+
+// Register registers Decoder as the decoder of application/cbor bodies.
+func Register() {
+	binding.RegisterDecoder(MediaType, Decoder{})
+}
+
+// Decoder decodes CBOR bodies, see the package documentation.
+type Decoder struct{}
+
+var _ binding.UnknownFieldsDecoder = Decoder{}
+
+func (Decoder) Decode(body []byte, ptr interface{}) error {
+	data, err := ToJSON(body)
+	if err != nil {
+		return err
+	}
+	// type errors keep the field path of the value, their position is the one in the JSON
+	return json.Unmarshal(data, ptr)
+}
+
+func (Decoder) UnknownFields(body []byte, ptr interface{}) ([]string, error) {
+	data, err := ToJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	return bindingerrors.UnknownJSONFields(data, ptr)
+}
+
+// ToJSON converts the CBOR data item of body into JSON. Truncated bodies fail with
+// io.ErrUnexpectedEOF, malformed ones with *bindingerrors.SyntaxError.
+func ToJSON(body []byte) ([]byte, error) {
+	d := &decoder{data: body}
+	v, err := d.value(0)
+	err = d.unexpected(err, "break")
+	if err == nil && d.off < len(d.data) {
+		err = d.errorf("extra data after the top-level value")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+// errBreak is returned by value for the break stop code of indefinite-length items.
+var errBreak = errors.New("break")
+
+// unexpected converts errBreak into a syntax error at the break, where what was expected.
+func (d *decoder) unexpected(err error, what string) error {
+	if err != errBreak {
+		return err
+	}
+	d.off--
+	if what == "break" {
+		return d.errorf("unexpected break")
+	}
+	return d.errorf("missing %s", what)
+}
+
+func (d *decoder) errorf(format string, a ...interface{}) error {
+	return &bindingerrors.SyntaxError{Format: "CBOR", Err: fmt.Errorf("offset %d: "+format, append([]interface{}{d.off}, a...)...)}
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// head reads the initial byte and the argument of a data item. Indefinite-length items and the
+// break stop code have the additional information 31 and no argument.
+func (d *decoder) head() (major byte, info byte, arg uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		b, err := d.next(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		switch len(b) {
+		case 1:
+			arg = uint64(b[0])
+		case 2:
+			arg = uint64(binary.BigEndian.Uint16(b))
+		case 4:
+			arg = uint64(binary.BigEndian.Uint32(b))
+		default:
+			arg = binary.BigEndian.Uint64(b)
+		}
+		return major, info, arg, nil
+	case info == 31 && major >= 2 && major != 6:
+		return major, info, 0, nil
+	}
+	d.off--
+	return 0, 0, 0, d.errorf("invalid additional information %d", info)
+}
+
+// value returns the next data item as a value of encoding/json: nil, bool, json.Number, float64,
+// string, []byte, []interface{} or map[string]interface{}.
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, d.errorf("exceeded max depth of %d", maxDepth)
+	}
+	start := d.off
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	indefinite := info == 31
+
+	switch major {
+	case 0:
+		return json.Number(strconv.FormatUint(arg, 10)), nil
+	case 1:
+		n := new(big.Int).SetUint64(arg)
+		return json.Number(n.Neg(n.Add(n, big.NewInt(1))).String()), nil
+	case 2, 3:
+		b, err := d.bytes(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == 2 {
+			return b, nil
+		}
+		if !utf8.Valid(b) {
+			d.off = start
+			return nil, d.errorf("invalid UTF-8 in text string")
+		}
+		return string(b), nil
+	case 4:
+		// every element takes at least a byte, larger lengths are truncated bodies
+		if !indefinite && arg > uint64(len(d.data)-d.off) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		a := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			v, err := d.value(depth + 1)
+			if err == errBreak && indefinite {
+				break
+			}
+			if err != nil {
+				return nil, d.unexpected(err, "array element")
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case 5:
+		if !indefinite && arg > uint64(len(d.data)-d.off)/2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		m := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			keyStart := d.off
+			k, err := d.value(depth + 1)
+			if err == errBreak && indefinite {
+				break
+			}
+			if err != nil {
+				return nil, d.unexpected(err, "map key")
+			}
+			key, ok := mapKey(k)
+			if !ok {
+				d.off = keyStart
+				return nil, d.errorf("map keys must be text strings or integers")
+			}
+			if m[key], err = d.value(depth + 1); err != nil {
+				return nil, d.unexpected(err, "map value")
+			}
+		}
+		return m, nil
+	case 6:
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, d.unexpected(err, "tag content")
+		}
+		return d.tagged(start, arg, v)
+	}
+
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25, 26, 27:
+		var f float64
+		switch info {
+		case 25:
+			f = halfToFloat(uint16(arg))
+		case 26:
+			f = float64(math.Float32frombits(uint32(arg)))
+		default:
+			f = math.Float64frombits(arg)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			d.off = start
+			return nil, d.errorf("%v is not supported", f)
+		}
+		return f, nil
+	case 31:
+		return nil, errBreak
+	}
+	d.off = start
+	return nil, d.errorf("unsupported simple value %d", arg)
+}
+
+// bytes returns the content of a byte or text string, concatenating the chunks of
+// indefinite-length strings.
+func (d *decoder) bytes(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.next(n)
+	}
+	b := []byte{}
+	for {
+		start := d.off
+		m, info, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if m == 7 && info == 31 {
+			return b, nil
+		}
+		if m != major || info == 31 {
+			d.off = start
+			return nil, d.errorf("invalid chunk of indefinite-length string")
+		}
+		chunk, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+}
+
+// tagged returns the value of the tag number tag with the content v.
+func (d *decoder) tagged(start int, tag uint64, v interface{}) (interface{}, error) {
+	switch tag {
+	case 1:
+		var sec float64
+		switch v := v.(type) {
+		case json.Number:
+			sec, _ = v.Float64() // integers always parse
+		case float64:
+			sec = v
+		default:
+			d.off = start
+			return nil, d.errorf("epoch-based date/time must be a number")
+		}
+		whole, frac := math.Modf(sec)
+		return time.Unix(int64(whole), int64(frac*1e9)).UTC().Format(time.RFC3339Nano), nil
+	case 2, 3:
+		b, ok := v.([]byte)
+		if !ok {
+			d.off = start
+			return nil, d.errorf("bignum must be a byte string")
+		}
+		n := new(big.Int).SetBytes(b)
+		if tag == 3 {
+			n.Neg(n.Add(n, big.NewInt(1)))
+		}
+		return json.Number(n.String()), nil
+	}
+	return v, nil
+}
+
+// mapKey returns the JSON object key of the map key k.
+func mapKey(k interface{}) (string, bool) {
+	switch k := k.(type) {
+	case string:
+		return k, true
+	case json.Number:
+		return string(k), true
+	}
+	return "", false
+}
+
+// halfToFloat converts an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
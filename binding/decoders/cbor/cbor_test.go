@@ -0,0 +1,127 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+)
+
+// TestToJSON converts the examples of RFC 8949 appendix A that have a JSON representation.
+func TestToJSON(t *testing.T) {
+	cases := []struct {
+		cbor string
+		json string
+	}{
+		{"00", `0`},
+		{"1903e8", `1000`},
+		{"1bffffffffffffffff", `18446744073709551615`},
+		{"c249010000000000000000", `18446744073709551616`},
+		{"3bffffffffffffffff", `-18446744073709551616`},
+		{"3903e7", `-1000`},
+		{"f93c00", `1`},
+		{"f9c400", `-4`},
+		{"f90001", `5.960464477539063e-8`},
+		{"fa47c35000", `100000`},
+		{"fb3ff199999999999a", `1.1`},
+		{"f4", `false`},
+		{"f5", `true`},
+		{"f6", `null`},
+		{"f7", `null`},
+		{"c11a514b67b0", `"2013-03-21T20:04:00Z"`},
+		{"c074323031332d30332d32315432303a30343a30305a", `"2013-03-21T20:04:00Z"`},
+		{"4401020304", `"AQIDBA=="`},
+		{"62c3bc", `"ü"`},
+		{"83010203", `[1,2,3]`},
+		{"a201020304", `{"1":2,"3":4}`},
+		{"a26161016162820203", `{"a":1,"b":[2,3]}`},
+		{"5f42010243030405ff", `"AQIDBAU="`},
+		{"7f657374726561646d696e67ff", `"streaming"`},
+		{"9f018202039f0405ffff", `[1,[2,3],[4,5]]`},
+		{"bf61610161629f0203ffff", `{"a":1,"b":[2,3]}`},
+	}
+	for _, c := range cases {
+		t.Run(c.cbor, func(t *testing.T) {
+			data, err := ToJSON(decodeHex(t, c.cbor))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != c.json {
+				t.Errorf("got %s, want %s", data, c.json)
+			}
+		})
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		cbor   string
+		syntax bool
+	}{
+		{"truncated", "1903", false},
+		{"truncated array", "830102", false},
+		{"huge array", "9b00ffffffffffffff", false},
+		{"extra data", "0000", true},
+		{"NaN", "f97e00", true},
+		{"infinity", "f97c00", true},
+		{"byte string key", "a1420102f6", true},
+		{"invalid UTF-8", "62c328", true},
+		{"reserved info", "1c", true},
+		{"break outside item", "ff", true},
+		{"nested chunk", "5f5fffff", true},
+		{"break in array", "8201ff", true},
+		{"break after key", "bf6161ff", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ToJSON(decodeHex(t, c.cbor))
+			var se *bindingerrors.SyntaxError
+			switch {
+			case c.syntax && !errors.As(err, &se):
+				t.Errorf("got %v, want a *SyntaxError", err)
+			case !c.syntax && err != io.ErrUnexpectedEOF:
+				t.Errorf("got %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+		})
+	}
+}
+
+type user struct {
+	Name    string    `json:"name"`
+	Avatar  []byte    `json:"avatar"`
+	Created time.Time `json:"created"`
+}
+
+func TestDecoder(t *testing.T) {
+	// {"name": "Badger", "avatar": h'0102', "created": 1(1363896240), "zip": 1}
+	body := decodeHex(t, "a4646e616d6566426164676572666176617461724201026763726561746564c11a514b67b0637a697001")
+	var u user
+	if err := (Decoder{}).Decode(body, &u); err != nil {
+		t.Fatal(err)
+	}
+	want := user{Name: "Badger", Avatar: []byte{1, 2}, Created: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("got %+v, want %+v", u, want)
+	}
+
+	unknown, err := (Decoder{}).UnknownFields(body, &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unknown, []string{"zip"}) {
+		t.Errorf("got unknown fields %v, want [zip]", unknown)
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
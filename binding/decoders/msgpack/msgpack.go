@@ -0,0 +1,290 @@
+// Package msgpack decodes MessagePack request bodies for binding.Bind. Bodies are converted into
+// JSON first, like YAML ones, so fields are named by their json tags and unknown fields are
+// reported like the ones of JSON bodies. Register it once, before the routes are served:
+//
+//	msgpack.Register()
+//
+// Binary values are decoded into []byte fields and timestamps (extension type -1) into time.Time
+// fields. Other extension types, NaN and infinite floats can't be represented in JSON and are
+// rejected.
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tamalsaha/learn-chi/binding"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+)
+
+// MediaType is the media type of MessagePack bodies.
+const MediaType = "application/msgpack"
+
+// maxDepth is the maximum nesting of arrays and maps of a body.
+const maxDepth = 1000
+
+// Register registers Decoder as the decoder of application/msgpack and application/x-msgpack bodies.
+func Register() {
+	binding.RegisterDecoder(MediaType, Decoder{})
+	binding.RegisterDecoder("application/x-msgpack", Decoder{})
+}
+
+// Decoder decodes MessagePack bodies, see the package documentation.
+type Decoder struct{}
+
+var _ binding.UnknownFieldsDecoder = Decoder{}
+
+func (Decoder) Decode(body []byte, ptr interface{}) error {
+	data, err := ToJSON(body)
+	if err != nil {
+		return err
+	}
+	// type errors keep the field path of the value, their position is the one in the JSON
+	return json.Unmarshal(data, ptr)
+}
+
+func (Decoder) UnknownFields(body []byte, ptr interface{}) ([]string, error) {
+	data, err := ToJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	return bindingerrors.UnknownJSONFields(data, ptr)
+}
+
+// ToJSON converts the MessagePack object of body into JSON. Truncated bodies fail with
+// io.ErrUnexpectedEOF, malformed ones with *bindingerrors.SyntaxError.
+func ToJSON(body []byte) ([]byte, error) {
+	d := &decoder{data: body}
+	v, err := d.value(0)
+	if err == nil && d.off < len(d.data) {
+		err = d.errorf("extra data after the top-level value")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) errorf(format string, a ...interface{}) error {
+	return &bindingerrors.SyntaxError{Format: "MessagePack", Err: fmt.Errorf("offset %d: "+format, append([]interface{}{d.off}, a...)...)}
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// value returns the next object as a value of encoding/json: nil, bool, json.Number, float64,
+// string, []byte, []interface{} or map[string]interface{}.
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, d.errorf("exceeded max depth of %d", maxDepth)
+	}
+	start := d.off
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return json.Number(strconv.Itoa(int(c))), nil
+	case c >= 0xe0:
+		return json.Number(strconv.Itoa(int(int8(c)))), nil
+	case c <= 0x8f:
+		return d.object(depth, uint64(c&0x0f))
+	case c <= 0x9f:
+		return d.array(depth, uint64(c&0x0f))
+	case c <= 0xbf:
+		return d.str(start, uint64(c&0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.next(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(start, n)
+	case 0xca, 0xcb:
+		n, err := d.uint(4 << (c - 0xca))
+		if err != nil {
+			return nil, err
+		}
+		f := math.Float64frombits(n)
+		if c == 0xca {
+			f = float64(math.Float32frombits(uint32(n)))
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			d.off = start
+			return nil, d.errorf("%v is not supported", f)
+		}
+		return f, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatUint(n, 10)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// sign-extend the value of size bytes
+		shift := 64 - 8*uint(size)
+		return json.Number(strconv.FormatInt(int64(n<<shift)>>shift, 10)), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(start, 1<<(c-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(start, n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(depth, n)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.object(depth, n)
+	}
+	d.off = start
+	return nil, d.errorf("invalid type 0x%x", c)
+}
+
+func (d *decoder) str(start int, n uint64) (interface{}, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		d.off = start
+		return nil, d.errorf("invalid UTF-8 in string")
+	}
+	return string(b), nil
+}
+
+func (d *decoder) array(depth int, n uint64) (interface{}, error) {
+	// every element takes at least a byte, larger lengths are truncated bodies
+	if n > uint64(len(d.data)-d.off) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	a := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (d *decoder) object(depth int, n uint64) (interface{}, error) {
+	if n > uint64(len(d.data)-d.off)/2 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	m := make(map[string]interface{}, n)
+	for i := uint64(0); i < n; i++ {
+		keyStart := d.off
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		switch k := k.(type) {
+		case string:
+			key = k
+		case json.Number:
+			key = string(k)
+		default:
+			d.off = keyStart
+			return nil, d.errorf("map keys must be strings or integers")
+		}
+		if m[key], err = d.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ext returns the value of an extension of n bytes, only timestamps are supported.
+func (d *decoder) ext(start int, n uint64) (interface{}, error) {
+	b, err := d.next(n + 1)
+	if err != nil {
+		return nil, err
+	}
+	typ, data := int8(b[0]), b[1:]
+	if typ != -1 {
+		d.off = start
+		return nil, d.errorf("unsupported extension type %d", typ)
+	}
+
+	var sec, nsec int64
+	switch len(data) {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(data))
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		sec, nsec = int64(v&(1<<34-1)), int64(v>>34)
+	case 12:
+		nsec, sec = int64(binary.BigEndian.Uint32(data)), int64(binary.BigEndian.Uint64(data[4:]))
+	default:
+		d.off = start
+		return nil, d.errorf("invalid timestamp of %d bytes", len(data))
+	}
+	return time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano), nil
+}
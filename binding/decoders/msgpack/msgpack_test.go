@@ -0,0 +1,122 @@
+package msgpack
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+)
+
+func TestToJSON(t *testing.T) {
+	cases := []struct {
+		msgpack string
+		json    string
+	}{
+		{"00", `0`},
+		{"7f", `127`},
+		{"ff", `-1`},
+		{"e0", `-32`},
+		{"cc80", `128`},
+		{"cd03e8", `1000`},
+		{"cfffffffffffffffff", `18446744073709551615`},
+		{"d080", `-128`},
+		{"d1fc18", `-1000`},
+		{"d3ffffffffffffffff", `-1`},
+		{"ca3f800000", `1`},
+		{"cb3ff199999999999a", `1.1`},
+		{"c0", `null`},
+		{"c2", `false`},
+		{"c3", `true`},
+		{"a3616263", `"abc"`},
+		{"d903616263", `"abc"`},
+		{"c40401020304", `"AQIDBA=="`},
+		{"93010203", `[1,2,3]`},
+		{"dc0003010203", `[1,2,3]`},
+		{"82a16101a162920203", `{"a":1,"b":[2,3]}`},
+		{"8201020304", `{"1":2,"3":4}`},
+		{"d6ff514b67b0", `"2013-03-21T20:04:00Z"`},
+		{"d7ff00000004514b67b0", `"2013-03-21T20:04:00.000000001Z"`},
+		{"c70cff00000001ffffffffffffffff", `"1969-12-31T23:59:59.000000001Z"`},
+	}
+	for _, c := range cases {
+		t.Run(c.msgpack, func(t *testing.T) {
+			data, err := ToJSON(decodeHex(t, c.msgpack))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != c.json {
+				t.Errorf("got %s, want %s", data, c.json)
+			}
+		})
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		msgpack string
+		syntax  bool
+	}{
+		{"truncated", "cd03", false},
+		{"truncated array", "930102", false},
+		{"huge map", "dfffffffff", false},
+		{"extra data", "0000", true},
+		{"never used", "c1", true},
+		{"NaN", "ca7fc00000", true},
+		{"binary key", "81c4010102", true},
+		{"invalid UTF-8", "a2c328", true},
+		{"unknown extension", "d40101", true},
+		{"invalid timestamp", "d5ff0001", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ToJSON(decodeHex(t, c.msgpack))
+			var se *bindingerrors.SyntaxError
+			switch {
+			case c.syntax && !errors.As(err, &se):
+				t.Errorf("got %v, want a *SyntaxError", err)
+			case !c.syntax && err != io.ErrUnexpectedEOF:
+				t.Errorf("got %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+		})
+	}
+}
+
+type user struct {
+	Name    string    `json:"name"`
+	Avatar  []byte    `json:"avatar"`
+	Created time.Time `json:"created"`
+}
+
+func TestDecoder(t *testing.T) {
+	// {"name": "Badger", "avatar": bin(0102), "created": timestamp(1363896240), "zip": 1}
+	body := decodeHex(t, "84a46e616d65a6426164676572a6617661746172c4020102a763726561746564d6ff514b67b0a37a697001")
+	var u user
+	if err := (Decoder{}).Decode(body, &u); err != nil {
+		t.Fatal(err)
+	}
+	want := user{Name: "Badger", Avatar: []byte{1, 2}, Created: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("got %+v, want %+v", u, want)
+	}
+
+	unknown, err := (Decoder{}).UnknownFields(body, &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unknown, []string{"zip"}) {
+		t.Errorf("got unknown fields %v, want [zip]", unknown)
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
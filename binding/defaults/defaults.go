@@ -0,0 +1,211 @@
+// Package defaults sets the defaults of objects bound to a request before they are validated.
+// Defaults are declared with struct tags, eg. `default:"10"`, and with defaulting funcs registered
+// per type, in the spirit of the SetDefaults_ funcs of k8s API types.
+package defaults
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tag is the struct tag holding the default value of a field. Values of strings, booleans and
+// numbers are parsed like strconv does, time.Duration like time.ParseDuration, types implementing
+// encoding.TextUnmarshaler using UnmarshalText and slices, maps and structs as JSON, eg.
+//
+//	Limit   int           `default:"10"`
+//	Timeout time.Duration `default:"30s"`
+//	Tags    []string      `default:"[\"a\", \"b\"]"`
+var Tag = "default"
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+var registry = struct {
+	sync.RWMutex
+	funcs map[reflect.Type]reflect.Value
+}{funcs: map[reflect.Type]reflect.Value{}}
+
+// Register registers fn, a func(*T), as the defaulting func of T, eg.
+//
+//	defaults.Register(func(u *User) {
+//		if u.DisplayName == "" {
+//			u.DisplayName = u.FirstName + " " + u.LastName
+//		}
+//	})
+//
+// Funcs may be registered while requests are served, the registry is locked. Registering a func
+// for a type again replaces it.
+func Register(fn interface{}) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 || t.In(0).Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("defaults: defaulting func must be a func(*T), found %s", t))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.funcs[t.In(0).Elem()] = v
+}
+
+func funcOf(t reflect.Type) (reflect.Value, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.funcs[t]
+	return fn, ok
+}
+
+// Apply sets the defaults of the object ptr points to. The fields of a struct that are zero are
+// set to the values of their default tags first, then the defaulting func of the struct is called
+// and finally the defaults of its fields are applied, including the elements of slices and maps
+// and the values of non-nil pointers. So the defaulting func of a struct may add elements that
+// are defaulted in turn. Unexported fields and values held by interfaces are skipped.
+//
+// Errors are caused by invalid default tags and indicate a bug in the source code.
+func Apply(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("defaults: Apply requires a non-nil pointer, found %T", ptr)
+	}
+	return apply(v.Elem())
+}
+
+func apply(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return apply(v.Elem())
+		}
+	case reflect.Struct:
+		return applyStruct(v)
+	case reflect.Slice, reflect.Array:
+		if !mayHaveDefaults(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := apply(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !mayHaveDefaults(v.Type().Elem()) {
+			return nil
+		}
+		// map elements are not addressable, default a copy and store it back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := apply(elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+func applyStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		s, ok := fld.Tag.Lookup(Tag)
+		if !ok || fld.PkgPath != "" || !v.Field(i).IsZero() {
+			continue
+		}
+		dv, err := parse(fld.Type, s)
+		if err != nil {
+			return fmt.Errorf("defaults: invalid default of %s.%s: %w", t, fld.Name, err)
+		}
+		v.Field(i).Set(dv)
+	}
+
+	if fn, ok := funcOf(t); ok {
+		fn.Call([]reflect.Value{v.Addr()})
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+		if err := apply(v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mayHaveDefaults returns false for the elements of slices and maps that have no fields.
+func mayHaveDefaults(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// parse returns the value of type t of the default tag s.
+func parse(t reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t.Kind() == reflect.Ptr {
+		ev, err := parse(t.Elem(), s)
+		if err != nil {
+			return v, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(ev)
+		v.Set(p)
+		return v, nil
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		return v, err
+	}
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return v, err
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if err := json.Unmarshal([]byte(s), v.Addr().Interface()); err != nil {
+			return v, err
+		}
+	default:
+		return v, fmt.Errorf("unsupported type %s", t)
+	}
+	return v, nil
+}
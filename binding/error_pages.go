@@ -0,0 +1,181 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/negotiation"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	httpw "go.wandrs.dev/http"
+	"go.wandrs.dev/inject"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorPages are the HTML pages of the errors of a route set by WithErrorPages.
+type ErrorPages struct {
+	// Templates are the names of the templates of the pages by status code, eg.
+	// {404: "errors/404", 422: "errors/422", 500: "errors/500"}. Templates are rendered by the
+	// render.Render passed to Injector, with an ErrorPage as their data.
+	Templates map[int]string
+	// Default is the name of the template of the codes without a template. If it is empty,
+	// errors of these codes are written as metav1.Status.
+	Default string
+	// Development adds the verbose error, its stack trace and the bound form values to the
+	// pages. As they expose internals, it must only be set in development, see
+	// responsewriters.ProductionMode.
+	Development bool
+}
+
+var errorPagesType = reflect.TypeOf(ErrorPages{})
+
+// template returns the name of the template of code.
+func (p ErrorPages) template(code int) string {
+	if name, ok := p.Templates[code]; ok {
+		return name
+	}
+	return p.Default
+}
+
+// ErrorPage is the data of the templates of ErrorPages.
+type ErrorPage struct {
+	// Code is the HTTP status code of the error, eg. 404.
+	Code int
+	// Title is the status text of the code, eg. Not Found.
+	Title string
+	// Message is the message of the status, translated like the ones of metav1.Status.
+	Message string
+	// Reason is the reason of the status, eg. NotFound.
+	Reason metav1.StatusReason
+	// Causes are the causes of the status, eg. the invalid fields of a 422 Unprocessable Entity.
+	Causes []metav1.StatusCause
+	// RequestID is the ID of the request set by chim.RequestID, to be quoted in bug reports.
+	RequestID string
+	// Status is the status of the error, as written to API clients.
+	Status *metav1.Status
+
+	// Development is true in development mode, which sets the fields below.
+	Development bool
+	// Error is the error, formatted with %+v.
+	Error string
+	// Stack is the stack trace of the error, recorded where the handler or provider returned it
+	// or where it was wrapped with responsewriters.WithStack.
+	Stack string
+	// Form are the form values of the request, if it was bound by Form or Bind.
+	Form []FormValue
+}
+
+// FormValue is a form value of an ErrorPage.
+type FormValue struct {
+	Name   string
+	Values []string
+}
+
+// WithErrorPages renders the errors of handlers and providers of the routes it is registered on
+// as HTML pages, if the client prefers text/html over the media types of metav1.Status, eg.
+// browsers. Errors of codes without a template are written as metav1.Status, eg.
+//
+//	r.Use(binding.WithErrorPages(binding.ErrorPages{
+//		Templates: map[int]string{404: "errors/404", 422: "errors/422"},
+//		Default:   "errors/500",
+//	}))
+//
+// Messages are translated and redacted in production mode like those of metav1.Status.
+func WithErrorPages(pages ErrorPages) func(next http.Handler) http.Handler {
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			injector.Map(pages)
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: "binding.WithErrorPages", provides: []reflect.Type{errorPagesType}})
+}
+
+// writeError writes err to the response, as an HTML page of the ErrorPages of the route if the
+// client prefers text/html and as metav1.Status otherwise.
+func writeError(err error, w http.ResponseWriter, r *http.Request) {
+	injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+	if injector == nil {
+		responsewriters.ErrorNegotiated(err, negotiation.Default, w, r)
+		return
+	}
+	v := injector.GetVal(errorPagesType)
+	rv := injector.GetVal(httpwResponseWriterType)
+	if !v.IsValid() || !rv.IsValid() || !prefersHTML(r) {
+		responsewriters.ErrorNegotiated(err, negotiation.Default, w, r)
+		return
+	}
+
+	pages := v.Interface().(ErrorPages)
+	rw := rv.Interface().(httpw.ResponseWriter)
+	status := responsewriters.ErrorStatus(err, r)
+	name := pages.template(int(status.Code))
+	if name == "" || rw.TemplateLookup(name) == nil {
+		responsewriters.StatusNegotiated(status, negotiation.Default, w, r)
+		return
+	}
+
+	if status.Details != nil && status.Details.RetryAfterSeconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(status.Details.RetryAfterSeconds)))
+	}
+	w.Header().Add("Vary", "Accept")
+	rw.HTML(int(status.Code), name, newErrorPage(err, status, pages.Development, r))
+}
+
+var httpwResponseWriterType = reflect.TypeOf((*httpw.ResponseWriter)(nil)).Elem()
+
+// newErrorPage returns the data of the error page of err.
+func newErrorPage(err error, status *metav1.Status, development bool, r *http.Request) *ErrorPage {
+	page := &ErrorPage{
+		Code:      int(status.Code),
+		Title:     http.StatusText(int(status.Code)),
+		Message:   status.Message,
+		Reason:    status.Reason,
+		RequestID: chim.GetReqID(r.Context()),
+		Status:    status,
+	}
+	if status.Details != nil {
+		page.Causes = status.Details.Causes
+	}
+	if development {
+		page.Development = true
+		page.Error = fmt.Sprintf("%+v", err)
+		page.Stack, _ = responsewriters.StackOf(err)
+		page.Form = formValues(r.Form)
+	}
+	return page
+}
+
+// formValues returns the parsed form values, sorted by name. The body is not parsed again,
+// so only forms bound by Form or Bind are returned.
+func formValues(form url.Values) []FormValue {
+	values := make([]FormValue, 0, len(form))
+	for name, v := range form {
+		values = append(values, FormValue{Name: name, Values: v})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values
+}
+
+// htmlSerializer stands for text/html in the negotiation of errors.
+type htmlSerializer struct {
+	negotiation.Serializer
+}
+
+func (htmlSerializer) MediaType() string        { return "text/html" }
+func (htmlSerializer) Accepts(interface{}) bool { return true }
+
+// prefersHTML returns true if the client of r prefers text/html over the media types of metav1.Status.
+func prefersHTML(r *http.Request) bool {
+	serializers := append(append([]negotiation.Serializer{}, negotiation.Default.Serializers()...), htmlSerializer{negotiation.Text})
+	s, err := negotiation.New(serializers...).NegotiateOutputMediaType(r, &metav1.Status{})
+	return err == nil && s.MediaType() == "text/html"
+}
@@ -0,0 +1,45 @@
+package binding_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/unrolled/render"
+)
+
+//go:noinline
+func failingHandler() error {
+	return errors.New("pq: connection refused")
+}
+
+// TestErrorPageStack checks that development error pages show the stack trace recorded where
+// the handler returned the error, and that other pages don't.
+func TestErrorPageStack(t *testing.T) {
+	for _, development := range []bool{false, true} {
+		r := chi.NewRouter()
+		r.Use(binding.Injector(render.New(render.Options{Directory: "../templates", Extensions: []string{".tmpl"}})))
+		r.Use(binding.WithErrorPages(binding.ErrorPages{
+			Templates:   map[int]string{http.StatusInternalServerError: "errors/500"},
+			Development: development,
+		}))
+		r.Get("/", binding.Handler(failingHandler))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		body := w.Body.String()
+		if w.Code != http.StatusInternalServerError || !strings.Contains(w.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("development=%v: got %d %s", development, w.Code, w.Header().Get("Content-Type"))
+		}
+		if got := strings.Contains(body, "Stack trace") && strings.Contains(body, "binding.Handler"); got != development {
+			t.Errorf("development=%v: got a stack trace %v:\n%s", development, got, body)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package errors converts the errors returned while binding a request to an object,
+// eg. form.DecodeErrors, validator.ValidationErrors and JSON errors, into metav1.Status.
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/form/v4"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/tamalsaha/learn-chi/i18n"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NewBindingError returns an error indicating the request is invalid and cannot be bound to obj.
+// The StatusDetails are filled with the qualified kind registered for the type of obj and
+// the name of obj. The fields of the causes are named using FieldPathTag. It returns nil if err is nil.
+//
+//   - validator.ValidationErrors and ParamErrors from validation result in 422 Unprocessable Entity
+//   - form.DecodeErrors, ParamErrors, JSONError, SyntaxError, UnknownFieldsError and JSON syntax or type errors result in 400 Bad Request
+//   - ItemErrors result in 422 Unprocessable Entity if every item fails validation and 400 Bad Request otherwise
+//   - errors due to bugs in the source code, eg. decoding into a non pointer, result in 500 Internal Server Error
+//   - API errors are returned as is
+//
+// Messages are in the fallback language of i18n.Default, use NewLocalizedBindingError to
+// translate them into the language of a request.
+func NewBindingError(err error, obj interface{}) *apierrors.StatusError {
+	return NewLocalizedBindingError(err, obj, nil)
+}
+
+// NewLocalizedBindingError is NewBindingError with messages translated by trans, eg. the
+// translator returned by i18n.FromRequest. Validator tags missing from the catalog are reported
+// using i18n.KeyValidation. A nil trans uses the fallback language of i18n.Default.
+func NewLocalizedBindingError(err error, obj interface{}, trans ut.Translator) *apierrors.StatusError {
+	if err == nil {
+		return nil
+	}
+	switch err {
+	case io.EOF:
+		err = &JSONError{Err: ErrEmptyBody}
+	case io.ErrUnexpectedEOF:
+		err = &JSONError{Err: ErrTruncatedBody}
+	}
+
+	switch t := err.(type) {
+	case *apierrors.StatusError:
+		return t
+	case apierrors.APIStatus:
+		return &apierrors.StatusError{ErrStatus: t.Status()}
+	case *validator.InvalidValidationError, *form.InvalidDecoderError, *json.InvalidUnmarshalError:
+		return apierrors.NewInternalError(err) // error due to bug in source code
+	case validator.ValidationErrors:
+		causes := make([]metav1.StatusCause, 0, len(t))
+		for _, fe := range t {
+			field := fieldPath(obj, stripStructName(fe.StructNamespace()), "", FieldPathTag)
+			causes = append(causes, metav1.StatusCause{
+				Type:    causeType(fe),
+				Message: fieldErrorMessage(trans, fe, field),
+				Field:   field,
+			})
+		}
+		return newInvalid(obj, causes, trans)
+	case ParamErrors:
+		causes := make([]metav1.StatusCause, 0, len(t))
+		for _, e := range t {
+			msg := decodeErrorMessage(trans, e, e.Field())
+			if fe, ok := e.Err.(validator.FieldError); ok {
+				msg = fieldErrorMessage(trans, fe, e.Field())
+			}
+			causes = append(causes, metav1.StatusCause{
+				Type:    e.Type,
+				Message: msg,
+				Field:   e.Field(),
+			})
+		}
+		if t.invalid() {
+			return newInvalid(obj, causes, trans)
+		}
+		return newBadRequest(obj, causes, trans)
+	case form.DecodeErrors:
+		causes := make([]metav1.StatusCause, 0, len(t))
+		for field, err := range t {
+			field = fieldPath(obj, field, FormTag, FieldPathTag)
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: decodeErrorMessage(trans, err, field),
+				Field:   field,
+			})
+		}
+		sortCauses(causes)
+		return newBadRequest(obj, causes, trans)
+	case *UnknownFieldsError:
+		causes := make([]metav1.StatusCause, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			msg, _ := i18n.T(trans, i18n.KeyUnknownField, field)
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: msg,
+				Field:   field,
+			})
+		}
+		return newBadRequest(obj, causes, trans)
+	case *JSONError:
+		return newBadRequest(obj, []metav1.StatusCause{jsonCause(trans, t, obj)}, trans)
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return newBadRequest(obj, []metav1.StatusCause{jsonCause(trans, &JSONError{Err: err}, obj)}, trans)
+	case *SyntaxError:
+		return newBadRequest(obj, []metav1.StatusCause{syntaxCause(trans, t)}, trans)
+	case ItemErrors:
+		return newItemsError(t, obj, trans)
+	default:
+		return apierrors.NewBadRequest(err.Error()) // error due to bad input from request body
+	}
+}
+
+func causeType(fe validator.FieldError) metav1.CauseType {
+	if fe.Tag() == "required" {
+		return metav1.CauseTypeFieldValueRequired
+	}
+	return metav1.CauseTypeFieldValueInvalid
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldErrorMessage translates the message of the validator tag of fe. Tags whose message
+// depends on the kind of the field are looked up by tag.kind first, eg. min.string.
+func fieldErrorMessage(trans ut.Translator, fe validator.FieldError, field string) string {
+	var kind string
+	switch fe.Kind() {
+	case reflect.String:
+		kind = "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		kind = "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		kind = "number"
+	case reflect.Struct:
+		if fe.Type() == timeType {
+			kind = "time"
+		}
+	}
+	if kind != "" {
+		if msg, ok := i18n.T(trans, fe.Tag()+"."+kind, field, fe.Param()); ok {
+			return msg
+		}
+	}
+	if msg, ok := i18n.T(trans, fe.Tag(), field, fe.Param()); ok {
+		return msg
+	}
+	msg, _ := i18n.T(trans, i18n.KeyValidation, field, fe.Tag())
+	return msg
+}
+
+// decodeErrorMessage translates the message of a field that can't be decoded. The error of
+// the decoder is used if the catalog has no message for it.
+func decodeErrorMessage(trans ut.Translator, err error, field string) string {
+	if msg, ok := i18n.T(trans, i18n.KeyDecodeValue, field); ok {
+		return msg
+	}
+	return err.Error()
+}
+
+// jsonCause returns the cause of a request body that can't be decoded. The position of the
+// error is added to the message if it is known.
+func jsonCause(trans ut.Translator, e *JSONError, obj interface{}) metav1.StatusCause {
+	var cause metav1.StatusCause
+	switch t := e.Err.(type) {
+	case *json.UnmarshalTypeError:
+		cause.Type = metav1.CauseTypeFieldValueInvalid
+		cause.Field = jsonFieldPath(obj, t.Field)
+		cause.Message, _ = i18n.T(trans, i18n.KeyDecodeType, cause.Field, jsonTypeName(t.Type))
+		return cause // the field is more useful than the position
+	case *json.SyntaxError:
+		cause.Type = CauseTypeBodySyntaxInvalid
+		cause.Message, _ = i18n.T(trans, i18n.KeyBodySyntax, t.Error())
+	default:
+		switch e.Err {
+		case ErrEmptyBody:
+			cause.Type = CauseTypeBodyEmpty
+			cause.Message, _ = i18n.T(trans, i18n.KeyBodyEmpty)
+			return cause
+		case ErrTruncatedBody:
+			cause.Type = CauseTypeBodyTruncated
+			cause.Message, _ = i18n.T(trans, i18n.KeyBodyTruncated)
+		default:
+			cause.Message = e.Err.Error()
+		}
+	}
+	if e.Line > 0 {
+		cause.Message, _ = i18n.T(trans, i18n.KeyBodyPosition, cause.Message, strconv.Itoa(e.Line), strconv.Itoa(e.Column))
+	}
+	return cause
+}
+
+func sortCauses(causes []metav1.StatusCause) {
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Field < causes[j].Field
+	})
+}
+
+func details(obj interface{}, causes []metav1.StatusCause) (schema.GroupKind, string, *metav1.StatusDetails) {
+	qualifiedKind := QualifiedKindOf(obj)
+	name := NameOf(obj)
+	return qualifiedKind, name, &metav1.StatusDetails{
+		Group:  qualifiedKind.Group,
+		Kind:   qualifiedKind.Kind,
+		Name:   name,
+		Causes: causes,
+	}
+}
+
+func newInvalid(obj interface{}, causes []metav1.StatusCause, trans ut.Translator) *apierrors.StatusError {
+	qualifiedKind, name, d := details(obj, causes)
+	msg, _ := i18n.T(trans, i18n.KeyInvalid, qualifiedKind.String())
+	if name != "" {
+		msg, _ = i18n.T(trans, i18n.KeyInvalidNamed, qualifiedKind.String(), name)
+	}
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusUnprocessableEntity,
+		Reason:  metav1.StatusReasonInvalid,
+		Details: d,
+		Message: msg,
+	}}
+}
+
+func newBadRequest(obj interface{}, causes []metav1.StatusCause, trans ut.Translator) *apierrors.StatusError {
+	qualifiedKind, name, d := details(obj, causes)
+	msg, _ := i18n.T(trans, i18n.KeyDecode, qualifiedKind.String())
+	if name != "" {
+		msg, _ = i18n.T(trans, i18n.KeyDecodeNamed, qualifiedKind.String(), name)
+	}
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusBadRequest,
+		Reason:  metav1.StatusReasonBadRequest,
+		Details: d,
+		Message: msg,
+	}}
+}
@@ -0,0 +1,188 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/form/v4"
+	"github.com/go-playground/validator/v10"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	"github.com/tamalsaha/learn-chi/i18n"
+	"github.com/tamalsaha/learn-chi/problem"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// User contains user information
+type User struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Audit
+
+	FirstName string     `json:"firstName" validate:"required"`
+	Age       uint8      `json:"age" validate:"gte=0,lte=130"`
+	Email     string     `json:"email" validate:"required,email"`
+	Addresses []*Address `json:"addresses" validate:"required,dive,required"`
+}
+
+// Address houses a users address information
+type Address struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city" validate:"required"`
+}
+
+// Audit is embedded in User, so its fields are flattened.
+type Audit struct {
+	Revision int `json:"revision" validate:"gte=0"`
+}
+
+// Note is not registered, so its kind is the name of the type.
+type Note struct {
+	Text string `validate:"required"`
+}
+
+func init() {
+	bindingerrors.Register(User{}, schema.GroupKind{Group: "example.com", Kind: "User"})
+}
+
+// TestNewLocalizedBindingError checks the metav1.Status of every supported error shape and
+// language against the golden files in testdata, and that every status survives the conversion
+// into RFC 7807 problem details and back. Run it with -update to rewrite the golden files.
+func TestNewLocalizedBindingError(t *testing.T) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(bindingerrors.TagNameFunc("json"))
+	decoder := form.NewDecoder()
+
+	user := &User{
+		ObjectMeta: metav1.ObjectMeta{Name: "badger"},
+		FirstName:  "Badger",
+		Audit:      Audit{Revision: -1},
+		Age:        135,
+		Email:      "Badger.Smith",
+		Addresses:  []*Address{{Street: "Eavesdown Docks"}},
+	}
+
+	var syntaxErr, typeErr error
+	syntaxErr = json.Unmarshal([]byte(`{"firstName": "Badger",}`), &User{})
+	typeErr = json.Unmarshal([]byte(`{"age": "old"}`), &User{})
+
+	cases := []struct {
+		name string
+		err  error
+		obj  interface{}
+		lang string // empty for the fallback language
+	}{
+		{"nil", nil, user, ""},
+		{"validation-errors", validate.Struct(user), user, ""},
+		{"validation-errors-unregistered", validate.Struct(&Note{}), &Note{}, ""},
+		{"invalid-validation-error", validate.Struct(nil), user, ""},
+		{"form-decode-errors", decoder.Decode(&User{}, map[string][]string{"Age": {"old"}, "Revision": {"x"}, "Addresses[x].City": {"Persphone"}}), &User{}, ""},
+		{"form-invalid-decoder-error", decoder.Decode(User{}, map[string][]string{}), User{}, ""},
+		{"param-decode-errors", bindingerrors.ParamErrors{
+			{Location: "path", Name: "id", Type: metav1.CauseTypeFieldValueInvalid, Err: errors.New("Invalid Integer Value 'abc' Type 'int' Namespace 'id'")},
+			{Location: "query", Name: "limit", Type: metav1.CauseTypeFieldValueInvalid, Err: errors.New("Invalid Integer Value 'x' Type 'int' Namespace 'limit'")},
+		}, Note{}, ""},
+		{"param-validation-errors", paramValidationErrors(validate), Note{}, ""},
+		{"json-syntax-error", syntaxErr, &User{}, ""},
+		{"json-unmarshal-type-error", typeErr, &User{}, ""},
+		{"json-position-syntax-error", unmarshalJSON("{\n  \"firstName\": \"Badger\",\n}"), &User{}, ""},
+		{"json-position-type-error", unmarshalJSON(`{"addresses": [{"city": 42}]}`), &User{}, ""},
+		{"json-empty-body", unmarshalJSON(" \n"), &User{}, ""},
+		{"json-truncated-body", unmarshalJSON(`{"firstName": "Bad`), &User{}, ""},
+		{"unknown-fields", unknownFields(`{"firstName": "Badger", "nickname": "B", "addresses": [{"city": "Persephone", "zip": 1}]}`), &User{}, ""},
+		{"json-invalid-unmarshal-error", json.Unmarshal([]byte(`{}`), interface{}(User{})), User{}, ""},
+		{"api-status", apierrors.NewNotFound(schema.GroupResource{Group: "example.com", Resource: "users"}, "badger"), user, ""},
+		{"other-error", errors.New("unexpected EOF"), user, ""},
+		{"validation-errors-de", validate.Struct(user), user, "de"},
+		{"item-errors", bindingerrors.ItemErrors{
+			{Index: 1, Err: validate.Struct(user)},
+			{Index: 3, Err: typeErr},
+		}, &User{}, ""},
+		{"param-decode-errors-de", bindingerrors.ParamErrors{
+			{Location: "path", Name: "id", Type: metav1.CauseTypeFieldValueInvalid, Err: errors.New("Invalid Integer Value 'abc' Type 'int' Namespace 'id'")},
+		}, Note{}, "de"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := []byte("null")
+			if se := bindingerrors.NewLocalizedBindingError(c.err, c.obj, i18n.Default.Translator(c.lang)); se != nil {
+				got, _ = json.MarshalIndent(se.ErrStatus, "", "  ")
+				if err := roundTripProblem(se.ErrStatus); err != nil {
+					t.Error(err)
+				}
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", c.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("status differs from %s, run with -update to rewrite it\n--- want\n%s--- got\n%s", golden, want, got)
+			}
+		})
+	}
+}
+
+// roundTripProblem checks that status is kept by converting it into problem details and back.
+func roundTripProblem(status metav1.Status) error {
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	data, err := json.Marshal(problem.FromStatus(&status))
+	if err != nil {
+		return err
+	}
+	var p problem.Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if got := problem.ToStatus(&p); !reflect.DeepEqual(*got, status) {
+		return fmt.Errorf("problem details don't round trip:\n%s\n%+v\n%+v", data, status, *got)
+	}
+	return nil
+}
+
+// unmarshalJSON returns the error of decoding body into a User, as reported by binding.JSON.
+func unmarshalJSON(body string) error {
+	return bindingerrors.NewJSONError(json.Unmarshal([]byte(body), &User{}), []byte(body))
+}
+
+// unknownFields returns the error of decoding body into a User in strict mode, as reported by binding.JSON.
+func unknownFields(body string) error {
+	fields, err := bindingerrors.UnknownJSONFields([]byte(body), &User{})
+	if err != nil {
+		panic(err)
+	}
+	return &bindingerrors.UnknownFieldsError{Fields: fields}
+}
+
+// paramValidationErrors returns the ParamErrors of a required query parameter, as reported by binding.Params.
+func paramValidationErrors(validate *validator.Validate) error {
+	var errs bindingerrors.ParamErrors
+	for _, fe := range validate.Struct(&Note{}).(validator.ValidationErrors) {
+		errs = append(errs, bindingerrors.ParamError{
+			Location: "query",
+			Name:     strings.ToLower(fe.Field()),
+			Type:     metav1.CauseTypeFieldValueRequired,
+			Err:      fe,
+		})
+	}
+	return errs
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldPathTag is the struct tag used to name the fields of StatusCause.Field, so that clients
+// can map the causes back onto the fields of their payload, eg. addresses[0].city instead of
+// the Go path User.Addresses[0].City.
+var FieldPathTag = "json"
+
+// FormTag is the struct tag used by form.Decoder to name fields, used to translate the keys
+// of form.DecodeErrors.
+var FormTag = "form"
+
+// TagNameFunc returns a func that names struct fields using tag. It is registered on
+// validators, so that validator.FieldError.Namespace uses the same names as the payload:
+//
+//	validate.RegisterTagNameFunc(errors.TagNameFunc("json"))
+//
+// Fields without the tag are named using their Go name.
+func TagNameFunc(tag string) func(fld reflect.StructField) string {
+	return func(fld reflect.StructField) string {
+		name := tagName(fld, tag)
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return fld.Name
+		}
+		return name
+	}
+}
+
+// tagName returns the name of fld in tag, without options like omitempty.
+func tagName(fld reflect.StructField, tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return strings.Split(fld.Tag.Get(tag), ",")[0]
+}
+
+// fieldPath translates path, a field path of obj named using the from tag, eg. Addresses[0].City,
+// into the path named using the to tag, eg. addresses[0].city. An empty from tag means Go field
+// names. Fields without a FieldPathTag are named by their FormTag. Embedded structs without a name in the to tag are flattened like encoding/json does.
+// Parts of the path that can't be matched with the fields of obj are kept as is.
+func fieldPath(obj interface{}, path, from, to string) string {
+	t := baseType(obj)
+	var out []string
+	for path != "" {
+		// the next name followed by its indexes, eg. Addresses[0]
+		seg := path
+		if i := strings.IndexByte(path, '.'); i >= 0 {
+			seg, path = path[:i], path[i+1:]
+		} else {
+			path = ""
+		}
+		name, indexes := seg, ""
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			name, indexes = seg[:i], seg[i:]
+		}
+
+		chain := findField(t, name, from)
+		if chain == nil {
+			out = append(out, seg)
+			t = nil // unknown from here on
+			continue
+		}
+		for i, fld := range chain {
+			idx := ""
+			if i == len(chain)-1 {
+				idx = indexes
+			}
+			n := tagName(fld, to)
+			if n == "" && to == FieldPathTag {
+				n = tagName(fld, FormTag) // eg. the fields of binding.Form
+			}
+			if n != "" && n != "-" {
+				out = append(out, n+idx)
+			} else if !fld.Anonymous || idx != "" {
+				out = append(out, fld.Name+idx)
+			} // else embedded struct, flattened
+		}
+
+		fld := chain[len(chain)-1]
+		t = fld.Type
+		for n := strings.Count(indexes, "["); t != nil && n > 0; n-- {
+			t = elemType(t)
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+// findField returns the field of the struct type t named name in the from tag. Fields of
+// embedded structs are found too, since form.Decoder flattens them. The result is the chain
+// of fields leading to the field, eg. [ObjectMeta Name].
+func findField(t reflect.Type, name, from string) []reflect.StructField {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		n := tagName(fld, from)
+		if n == "" || n == "-" {
+			n = fld.Name
+		}
+		if n == name {
+			return []reflect.StructField{fld}
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if fld := t.Field(i); fld.Anonymous {
+			if chain := findField(fld.Type, name, from); chain != nil {
+				return append([]reflect.StructField{fld}, chain...)
+			}
+		}
+	}
+	return nil
+}
+
+// elemType returns the type of the elements of a slice, array or map.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// stripStructName removes the name of the validated struct from a validator namespace.
+func stripStructName(ns string) string {
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
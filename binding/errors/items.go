@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/tamalsaha/learn-chi/i18n"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ItemError is the error of an item of a request body holding a list of items, eg. a JSON array
+// or newline-delimited JSON.
+type ItemError struct {
+	// Index is the 0-based index of the item in the body.
+	Index int
+	// Err is the error decoding or validating the item.
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return "item " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// ItemErrors are the errors of the items of a request body, ordered by index. NewBindingError
+// converts them into a single metav1.Status with the causes of every item, whose fields are
+// prefixed with the index of the item, eg. [3].name.
+type ItemErrors []*ItemError
+
+func (e ItemErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ie := range e {
+		msgs = append(msgs, ie.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// newItemsError converts errs into 422 Unprocessable Entity if all items fail validation, and
+// 400 Bad Request otherwise. obj is an item.
+func newItemsError(errs ItemErrors, obj interface{}, trans ut.Translator) *apierrors.StatusError {
+	invalid := true
+	var causes []metav1.StatusCause
+	indexes := make([]string, 0, len(errs))
+	for _, e := range errs {
+		index := "[" + strconv.Itoa(e.Index) + "]"
+		indexes = append(indexes, index)
+
+		st := NewLocalizedBindingError(e.Err, obj, trans).ErrStatus
+		invalid = invalid && st.Code == http.StatusUnprocessableEntity
+		if st.Details == nil || len(st.Details.Causes) == 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: st.Message,
+				Field:   index,
+			})
+			continue
+		}
+		for _, c := range st.Details.Causes {
+			if c.Field == "" {
+				c.Field = index
+			} else {
+				c.Field = index + "." + c.Field
+			}
+			causes = append(causes, c)
+		}
+	}
+
+	qualifiedKind, _, d := details(obj, causes)
+	d.Name = "" // the name of an item is not the name of the list
+	msg, _ := i18n.T(trans, i18n.KeyItemsInvalid, qualifiedKind.String(), strings.Join(indexes, ", "))
+	st := metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusBadRequest,
+		Reason:  metav1.StatusReasonBadRequest,
+		Details: d,
+		Message: msg,
+	}
+	if invalid {
+		st.Code, st.Reason = http.StatusUnprocessableEntity, metav1.StatusReasonInvalid
+	}
+	return &apierrors.StatusError{ErrStatus: st}
+}
@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Cause types of request bodies that can't be decoded at all. Values of the wrong type are
+// reported as metav1.CauseTypeFieldValueInvalid.
+const (
+	// CauseTypeBodyEmpty is used if the request body is empty.
+	CauseTypeBodyEmpty metav1.CauseType = "BodyEmpty"
+	// CauseTypeBodyTruncated is used if the request body ends in the middle of a value.
+	CauseTypeBodyTruncated metav1.CauseType = "BodyTruncated"
+	// CauseTypeBodySyntaxInvalid is used if the request body is not valid JSON, YAML, etc.
+	CauseTypeBodySyntaxInvalid metav1.CauseType = "BodySyntaxInvalid"
+)
+
+var (
+	// ErrEmptyBody is the error of an empty request body.
+	ErrEmptyBody = stderrors.New("request body is empty")
+	// ErrTruncatedBody is the error of a request body that ends in the middle of a value.
+	ErrTruncatedBody = stderrors.New("request body is truncated")
+)
+
+// JSONError is an error decoding a JSON request body, with the position of the error in the body.
+type JSONError struct {
+	// Err is ErrEmptyBody, ErrTruncatedBody, *json.SyntaxError or *json.UnmarshalTypeError.
+	Err error
+	// Offset is the offset of the byte of the body that failed to decode, or the length of
+	// the body if it is truncated.
+	Offset int64
+	// Line and Column are the 1-based position of Offset in the body.
+	Line, Column int
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("json: line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// NewJSONError returns the error of decoding body, the bytes read from the request body, as
+// *JSONError. Both the errors of json.Unmarshal and json.Decoder are supported:
+//
+//   - io.EOF and syntax errors of bodies without a value result in ErrEmptyBody
+//   - io.ErrUnexpectedEOF and unexpected end of JSON input result in ErrTruncatedBody
+//   - *json.SyntaxError and *json.UnmarshalTypeError are kept
+//
+// Other errors, eg. *json.InvalidUnmarshalError, are returned as is. It returns nil if err is nil.
+func NewJSONError(err error, body []byte) error {
+	if err == nil {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 && (err == io.EOF || err == io.ErrUnexpectedEOF || isSyntaxError(err)) {
+		return &JSONError{Err: ErrEmptyBody, Line: 1, Column: 1}
+	}
+
+	var offset int64
+	switch t := err.(type) {
+	case *json.SyntaxError:
+		if strings.HasPrefix(t.Error(), "unexpected end of JSON input") {
+			err, offset = ErrTruncatedBody, int64(len(body))
+		} else {
+			offset = t.Offset - 1 // the offending byte
+		}
+	case *json.UnmarshalTypeError:
+		offset = t.Offset
+	default:
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		err, offset = ErrTruncatedBody, int64(len(body))
+	}
+	line, column := position(body, offset)
+	return &JSONError{Err: err, Offset: offset, Line: line, Column: column}
+}
+
+func isSyntaxError(err error) bool {
+	_, ok := err.(*json.SyntaxError)
+	return ok
+}
+
+// position returns the 1-based line and column of the byte at offset in body. The position
+// after the last byte is returned for offsets past the end of body.
+func position(body []byte, offset int64) (line, column int) {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+	before := body[:offset]
+	return bytes.Count(before, []byte{'\n'}) + 1, len(before) - bytes.LastIndexByte(before, '\n')
+}
+
+// jsonFieldPath translates the field of a json.UnmarshalTypeError, eg. addresses.0.city,
+// into a field path, eg. addresses[0].city. Segments are matched with the fields of obj, so
+// that slice indexes and map keys are told apart from field names.
+func jsonFieldPath(obj interface{}, field string) string {
+	if field == "" {
+		return ""
+	}
+	t := baseType(obj)
+	var out []string
+	for _, seg := range strings.Split(field, ".") {
+		if et := elemType(t); et != nil && len(out) > 0 {
+			out[len(out)-1] += "[" + seg + "]"
+			t = et
+			continue
+		}
+		out = append(out, seg)
+		if chain := findField(t, seg, FieldPathTag); chain != nil {
+			t = chain[len(chain)-1].Type
+		} else {
+			t = nil
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+// jsonTypeName returns the JSON name of the Go type t, eg. number for int, so that clients
+// don't need to know the Go types of the server.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64 encoded
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParamError is the error decoding or validating a single request parameter.
+type ParamError struct {
+	// Location is path, query, header or cookie.
+	Location string
+	Name     string
+	Type     metav1.CauseType
+	Err      error
+}
+
+func (e ParamError) Error() string {
+	what := fmt.Sprintf("%s parameter %q", e.Location, e.Name)
+	if e.Location == "" {
+		what = fmt.Sprintf("field %q", e.Name) // validated field not bound to a parameter
+	}
+	if fe, ok := e.Err.(validator.FieldError); ok {
+		return fmt.Sprintf("%s failed on the %q tag", what, fe.Tag())
+	}
+	return fmt.Sprintf("%s: %v", what, e.Err)
+}
+
+func (e ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Field returns the parameter as location.name, eg. query.limit.
+func (e ParamError) Field() string {
+	if e.Location == "" {
+		return e.Name
+	}
+	return e.Location + "." + e.Name
+}
+
+// ParamErrors are the errors binding request parameters. They are converted to metav1.Status
+// causes by NewBindingError, with the field of each cause set to ParamError.Field.
+type ParamErrors []ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// invalid returns true if all errors are validation errors.
+func (e ParamErrors) invalid() bool {
+	for _, err := range e {
+		if _, ok := err.Err.(validator.FieldError); !ok {
+			return false
+		}
+	}
+	return true
+}
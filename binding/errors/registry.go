@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var kinds = struct {
+	sync.RWMutex
+	m map[reflect.Type]schema.GroupKind
+}{m: map[reflect.Type]schema.GroupKind{}}
+
+// Register records the qualified kind of the type of obj. NewBindingError uses it to fill
+// the Group and Kind of the StatusDetails of errors binding requests to obj.
+// obj may be a value or a pointer, eg. Register(User{}, schema.GroupKind{Group: "example.com", Kind: "User"}).
+func Register(obj interface{}, qualifiedKind schema.GroupKind) {
+	kinds.Lock()
+	kinds.m[baseType(obj)] = qualifiedKind
+	kinds.Unlock()
+}
+
+// QualifiedKindOf returns the qualified kind registered for the type of obj. The kind of
+// unregistered types is the name of the type without its package.
+func QualifiedKindOf(obj interface{}) schema.GroupKind {
+	t := baseType(obj)
+	if t == nil {
+		return schema.GroupKind{}
+	}
+	kinds.RLock()
+	gk, ok := kinds.m[t]
+	kinds.RUnlock()
+	if ok {
+		return gk
+	}
+	return schema.GroupKind{Kind: t.Name()}
+}
+
+// NameOf returns the name of obj if it has a GetName method, like metav1.Object.
+func NameOf(obj interface{}) string {
+	if o, ok := obj.(interface{ GetName() string }); ok && !isNil(obj) {
+		return o.GetName()
+	}
+	// metav1.ObjectMeta has pointer receivers, so structs embedding it are checked using a pointer to a copy
+	if v := reflect.ValueOf(obj); v.IsValid() && v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		if o, ok := p.Interface().(interface{ GetName() string }); ok {
+			return o.GetName()
+		}
+	}
+	return ""
+}
+
+// baseType returns the type of obj with pointers dereferenced.
+func baseType(obj interface{}) reflect.Type {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isNil(obj interface{}) bool {
+	v := reflect.ValueOf(obj)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
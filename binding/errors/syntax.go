@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"strconv"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/tamalsaha/learn-chi/i18n"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SyntaxError is a syntax error of a request body in a format other than JSON, eg. YAML or XML.
+// JSON bodies report syntax errors as *JSONError.
+type SyntaxError struct {
+	// Format is the name of the format of the body, eg. YAML.
+	Format string
+	// Err is the error of the decoder.
+	Err error
+	// Line is the 1-based line of the error, or 0 if it is unknown.
+	Line int
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line > 0 {
+		return e.Format + ": line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+	}
+	return e.Format + ": " + e.Err.Error()
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// syntaxCause returns the cause of a request body with a syntax error.
+func syntaxCause(trans ut.Translator, e *SyntaxError) metav1.StatusCause {
+	cause := metav1.StatusCause{Type: CauseTypeBodySyntaxInvalid}
+	cause.Message, _ = i18n.T(trans, i18n.KeyBodyFormat, e.Format, e.Err.Error())
+	if e.Line > 0 {
+		cause.Message, _ = i18n.T(trans, i18n.KeyBodyLine, cause.Message, strconv.Itoa(e.Line))
+	}
+	return cause
+}
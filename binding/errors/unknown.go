@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnknownFieldsError is the error of a request with fields that don't exist in the object it is
+// bound to. It is returned by strict decoding and converted into a cause per field by NewBindingError.
+type UnknownFieldsError struct {
+	// Fields are the paths of the unknown fields, eg. addresses[0].zip, sorted.
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	quoted := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		quoted = append(quoted, strconv.Quote(f))
+	}
+	return "unknown fields " + strings.Join(quoted, ", ")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnknownJSONFields returns the paths of the fields of the JSON body that don't exist in obj,
+// eg. addresses[0].zip. Like encoding/json, object keys are matched with the JSON names of
+// the fields case-insensitively. Values decoded by a json.Unmarshaler, maps and interface{}
+// fields accept any field.
+func UnknownJSONFields(body []byte, obj interface{}) ([]string, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	walkJSON(v, baseType(obj), "", &unknown)
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+func walkJSON(v interface{}, t reflect.Type, path string, unknown *[]string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Map:
+			for key, val := range v {
+				walkJSON(val, t.Elem(), path+"["+key+"]", unknown)
+			}
+		case reflect.Struct:
+			for key, val := range v {
+				p := key
+				if path != "" {
+					p = path + "." + key
+				}
+				chain := findJSONField(t, key)
+				if chain == nil {
+					*unknown = append(*unknown, p)
+					continue
+				}
+				walkJSON(val, chain[len(chain)-1].Type, p, unknown)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, val := range v {
+				walkJSON(val, t.Elem(), fmt.Sprintf("%s[%d]", path, i), unknown)
+			}
+		}
+	}
+}
+
+// findJSONField returns the chain of fields of the struct type t that encoding/json decodes
+// key into, preferring an exact match of the name over a case-insensitive one.
+func findJSONField(t reflect.Type, key string) []reflect.StructField {
+	if chain := findField(t, key, FieldPathTag); chain != nil && jsonName(chain[len(chain)-1]) != "-" {
+		return chain
+	}
+	return findFieldFold(t, key)
+}
+
+func findFieldFold(t reflect.Type, key string) []reflect.StructField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.PkgPath != "" && !fld.Anonymous {
+			continue // unexported
+		}
+		name := jsonName(fld)
+		if name == "-" {
+			continue
+		}
+		if fld.Anonymous && name == "" {
+			if chain := findFieldFold(fld.Type, key); chain != nil {
+				return append([]reflect.StructField{fld}, chain...)
+			}
+			continue
+		}
+		if name == "" {
+			name = fld.Name
+		}
+		if strings.EqualFold(name, key) {
+			return []reflect.StructField{fld}
+		}
+	}
+	return nil
+}
+
+func jsonName(fld reflect.StructField) string {
+	return tagName(fld, "json")
+}
+
+// UnknownFormFields returns the keys of values that don't exist in obj, whose fields are named
+// using tag like form.Decoder does, eg. Addresses[0].Zip.
+func UnknownFormFields(values url.Values, obj interface{}, tag string) []string {
+	var unknown []string
+	for key := range values {
+		if !formFieldExists(baseType(obj), key, tag) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// formFieldExists returns true if the form key, eg. Addresses[0].City or Labels[app], names
+// a field of t.
+func formFieldExists(t reflect.Type, key, tag string) bool {
+	for key != "" {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+			return true
+		}
+		switch {
+		case key[0] == '[':
+			end := strings.IndexByte(key, ']')
+			if end < 0 || elemType(t) == nil {
+				return false
+			}
+			t, key = elemType(t), key[end+1:]
+		case key[0] == '.':
+			key = key[1:]
+		default:
+			end := strings.IndexAny(key, ".[")
+			if end < 0 {
+				end = len(key)
+			}
+			chain := findField(t, key[:end], tag)
+			if chain == nil {
+				return false
+			}
+			t, key = chain[len(chain)-1].Type, key[end:]
+		}
+	}
+	return true
+}
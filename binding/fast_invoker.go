@@ -0,0 +1,40 @@
+package binding
+
+import (
+	"reflect"
+	"sync"
+
+	"go.wandrs.dev/inject"
+)
+
+// FastInvokerFunc wraps a handler in an inject.FastInvoker with the same signature.
+type FastInvokerFunc func(fn interface{}) inject.FastInvoker
+
+var fastInvokers = struct {
+	sync.RWMutex
+	m map[reflect.Type]FastInvokerFunc
+}{m: map[reflect.Type]FastInvokerFunc{}}
+
+// RegisterFastInvoker registers the inject.FastInvoker adapter for handlers of type typ.
+// Handler uses the adapter to call handlers of that type without reflection.
+// It is called from the init funcs generated by cmd/fastinvoker-gen.
+func RegisterFastInvoker(typ reflect.Type, fn FastInvokerFunc) {
+	fastInvokers.Lock()
+	fastInvokers.m[typ] = fn
+	fastInvokers.Unlock()
+}
+
+// fastInvoker returns fn wrapped in its registered inject.FastInvoker adapter,
+// or fn itself if no adapter is registered for its type.
+func fastInvoker(fn interface{}) interface{} {
+	if inject.IsFastInvoker(fn) {
+		return fn
+	}
+	fastInvokers.RLock()
+	adapter, ok := fastInvokers.m[reflect.TypeOf(fn)]
+	fastInvokers.RUnlock()
+	if !ok {
+		return fn
+	}
+	return adapter(fn)
+}
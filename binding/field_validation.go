@@ -0,0 +1,88 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	"github.com/tamalsaha/learn-chi/i18n"
+	"go.wandrs.dev/inject"
+)
+
+// FieldValidation controls how JSON and Params treat fields of a request that don't exist in
+// the object it is bound to, like the fieldValidation levels of the kube-apiserver.
+type FieldValidation string
+
+const (
+	// FieldValidationIgnore drops unknown fields silently. It is the default.
+	FieldValidationIgnore FieldValidation = "Ignore"
+	// FieldValidationWarn drops unknown fields and adds a Warning header per field to the response.
+	FieldValidationWarn FieldValidation = "Warn"
+	// FieldValidationStrict fails the request with 400 Bad Request and a cause per unknown field.
+	FieldValidationStrict FieldValidation = "Strict"
+)
+
+var fieldValidationType = reflect.TypeOf(FieldValidation(""))
+
+// WithFieldValidation sets the field validation level of the routes it is registered on,
+// eg. r.With(binding.WithFieldValidation(binding.FieldValidationStrict), binding.JSON(User{})).
+func WithFieldValidation(level FieldValidation) func(next http.Handler) http.Handler {
+	switch level {
+	case FieldValidationIgnore, FieldValidationWarn, FieldValidationStrict:
+	default:
+		panic(fmt.Sprintf("binding: unknown field validation level %q", level))
+	}
+
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			injector.Map(level)
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: fmt.Sprintf("binding.WithFieldValidation(%s)", level), provides: []reflect.Type{fieldValidationType}})
+}
+
+// fieldValidationOf returns the field validation level of the route of r.
+func fieldValidationOf(r *http.Request) FieldValidation {
+	if injector, _ := r.Context().Value(injectorKey{}).(inject.Injector); injector != nil {
+		if v := injector.GetVal(fieldValidationType); v.IsValid() {
+			return v.Interface().(FieldValidation)
+		}
+	}
+	return FieldValidationIgnore
+}
+
+// checkUnknownFields applies the field validation level of the route of r to the fields
+// returned by unknown. unknown is only called if the fields are not ignored.
+func checkUnknownFields(w http.ResponseWriter, r *http.Request, unknown func() ([]string, error)) error {
+	level := fieldValidationOf(r)
+	if level == FieldValidationIgnore {
+		return nil
+	}
+	fields, err := unknown()
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+
+	if level == FieldValidationStrict {
+		return &bindingerrors.UnknownFieldsError{Fields: fields}
+	}
+	trans := i18n.FromRequest(r)
+	for _, f := range fields {
+		msg, _ := i18n.T(trans, i18n.KeyUnknownField, f)
+		addWarning(w, msg)
+	}
+	return nil
+}
+
+// addWarning adds a Warning header with the warn-code 299 (Miscellaneous Persistent Warning),
+// the format used by the kube-apiserver.
+func addWarning(w http.ResponseWriter, text string) {
+	w.Header().Add("Warning", "299 - "+strconv.Quote(text))
+}
@@ -0,0 +1,316 @@
+package binding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/form/v4"
+	"github.com/go-playground/validator/v10"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	"go.wandrs.dev/inject"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MultipartLimits limits the size of multipart/form-data requests bound by Form.
+type MultipartLimits struct {
+	// MaxMemory is the number of bytes of files kept in memory. Larger files are streamed
+	// by mime/multipart to temp files in os.TempDir, which are removed when the request completes.
+	MaxMemory int64
+	// MaxSize is the maximum size of the request body, 0 means the limit set by WithMaxBodySize.
+	// Larger requests fail with 413 Request Entity Too Large.
+	MaxSize int64
+}
+
+// DefaultMultipartLimits are the limits of routes without WithMultipartLimits.
+var DefaultMultipartLimits = MultipartLimits{MaxMemory: 32 << 20}
+
+var multipartLimitsType = reflect.TypeOf(MultipartLimits{})
+
+// WithMultipartLimits sets the multipart limits of the routes it is registered on,
+// eg. r.With(binding.WithMultipartLimits(binding.MultipartLimits{MaxMemory: 1 << 20, MaxSize: 100 << 20}), binding.Form(Upload{})).
+func WithMultipartLimits(limits MultipartLimits) func(next http.Handler) http.Handler {
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			injector.Map(limits)
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: fmt.Sprintf("binding.WithMultipartLimits(%+v)", limits), provides: []reflect.Type{multipartLimitsType}})
+}
+
+func multipartLimitsOf(r *http.Request) MultipartLimits {
+	if injector, _ := r.Context().Value(injectorKey{}).(inject.Injector); injector != nil {
+		if v := injector.GetVal(multipartLimitsType); v.IsValid() {
+			return v.Interface().(MultipartLimits)
+		}
+	}
+	return DefaultMultipartLimits
+}
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// formDecoder decodes the values of forms into fields named by their form tag or Go name.
+var formDecoder = func() *form.Decoder {
+	d := form.NewDecoder()
+	d.SetTagName(bindingerrors.FormTag)
+	return d
+}()
+
+// formFile is a top level field of a Form struct bound to uploaded files.
+type formFile struct {
+	index int
+	name  string // form key
+}
+
+// Form provides obj, a struct decoded from an application/x-www-form-urlencoded or
+// multipart/form-data request body, to the handlers of a router. Fields are named by their
+// form tag, like go-playground/form does, and top level fields of type *multipart.FileHeader
+// or []*multipart.FileHeader are set to the files uploaded with their name:
+//
+//	type Upload struct {
+//		Title  string                  `form:"title" validate:"required"`
+//		Avatar *multipart.FileHeader   `form:"avatar" validate:"required,maxsize=1Mi,contenttype=image/png image/jpeg"`
+//		Files  []*multipart.FileHeader `form:"files" validate:"max=5,dive,maxsize=10Mi"`
+//	}
+//
+// The size of multipart requests is limited per route using WithMultipartLimits. Like JSON, the
+// defaults of obj are applied and it is validated, unknown fields are handled according to the
+// WithFieldValidation level of the route, and the body is only read when a handler asks for obj.
+func Form(obj interface{}) func(next http.Handler) http.Handler {
+	ensureNotPointer(obj)
+	typ := reflect.TypeOf(obj)
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("binding: form must be a struct, found %s", typ))
+	}
+
+	files := formFiles(typ)
+	return bindProvider(typ, fmt.Sprintf("binding.Form(%s)", typ), func(w http.ResponseWriter, r *http.Request, ptr interface{}) error {
+		return bindForm(w, r, ptr, files)
+	})
+}
+
+// formFiles returns the top level fields of the struct type typ bound to uploaded files.
+func formFiles(typ reflect.Type) []formFile {
+	var files []formFile
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Type != fileHeaderType && f.Type != fileHeadersType {
+			continue
+		}
+		name := strings.Split(f.Tag.Get(bindingerrors.FormTag), ",")[0]
+		if name == "" || name == "-" {
+			name = f.Name
+		}
+		files = append(files, formFile{index: i, name: name})
+	}
+	return files
+}
+
+// bindForm decodes the form of r into ptr and validates it.
+func bindForm(w http.ResponseWriter, r *http.Request, ptr interface{}, files []formFile) error {
+	values, uploads, err := parseForm(r)
+	if err != nil {
+		return err
+	}
+	if err := formDecoder.Decode(ptr, values); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(ptr).Elem()
+	for _, f := range files {
+		fhs := uploads[f.name]
+		if len(fhs) == 0 {
+			continue
+		}
+		if fv := v.Field(f.index); fv.Type() == fileHeaderType {
+			fv.Set(reflect.ValueOf(fhs[0]))
+		} else {
+			fv.Set(reflect.ValueOf(fhs))
+		}
+	}
+
+	err = checkUnknownFields(w, r, func() ([]string, error) {
+		unknown := bindingerrors.UnknownFormFields(values, ptr, bindingerrors.FormTag)
+		for name := range uploads {
+			found := false
+			for _, f := range files {
+				found = found || f.name == name
+			}
+			if !found {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		return unknown, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := applyDefaults(ptr); err != nil {
+		return err
+	}
+	return validateRequest(r, ptr)
+}
+
+// parseForm parses the form of r within the multipart limits of its route. The temp files of
+// uploads are removed when the request completes.
+func parseForm(r *http.Request) (url.Values, map[string][]*multipart.FileHeader, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mediaTypeFormURLEncoded && mediaType != mediaTypeMultipartForm {
+		return nil, nil, unsupportedMediaType(mediaType, []string{mediaTypeFormURLEncoded, mediaTypeMultipartForm})
+	}
+
+	limits := multipartLimitsOf(r)
+	if limits.MaxSize == 0 {
+		limits.MaxSize = maxBodySizeOf(r)
+	}
+	limitBody(r, limits.MaxSize)
+	var err error
+	if mediaType == mediaTypeMultipartForm {
+		err = r.ParseMultipartForm(limits.MaxMemory)
+		if r.MultipartForm != nil {
+			AddCleanup(r, r.MultipartForm.RemoveAll)
+		}
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		if errors.Is(err, errBodyTooLarge) {
+			return nil, nil, bodyTooLarge(limits.MaxSize)
+		}
+		return nil, nil, apierrors.NewBadRequest(err.Error())
+	}
+	if r.MultipartForm == nil {
+		return r.PostForm, nil, nil
+	}
+	return r.MultipartForm.Value, r.MultipartForm.File, nil
+}
+
+// fileHeaderFunc is the value of multipart.FileHeader fields seen by the validator. The
+// validator ignores the tags of struct fields, except required, so file headers are validated
+// as a func returning them.
+type fileHeaderFunc func() *multipart.FileHeader
+
+func init() {
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) interface{} {
+		fh := v.Interface().(multipart.FileHeader)
+		if v.CanAddr() {
+			fh := v.Addr().Interface().(*multipart.FileHeader)
+			return fileHeaderFunc(func() *multipart.FileHeader { return fh })
+		}
+		return fileHeaderFunc(func() *multipart.FileHeader { return &fh })
+	}, multipart.FileHeader{})
+
+	RegisterValidation("maxsize", validateMaxSize)
+	RegisterValidation("contenttype", validateContentType)
+	registerParamCheck("maxsize", checkMaxSize)
+	registerParamCheck("contenttype", checkContentType)
+}
+
+// checkFileField checks that t is a file, the elements of []*multipart.FileHeader fields are
+// checked after dive.
+func checkFileField(t reflect.Type) error {
+	if t != fileHeaderType && t != fileHeaderType.Elem() {
+		return fmt.Errorf("requires a *multipart.FileHeader, found %s", t)
+	}
+	return nil
+}
+
+func checkMaxSize(t reflect.Type, param string) error {
+	if err := checkFileField(t); err != nil {
+		return err
+	}
+	_, err := resource.ParseQuantity(param)
+	return err
+}
+
+func checkContentType(t reflect.Type, param string) error {
+	if err := checkFileField(t); err != nil {
+		return err
+	}
+	if len(strings.Fields(param)) == 0 {
+		return errors.New("requires at least one media type")
+	}
+	for _, mediaType := range strings.Fields(param) {
+		if !strings.HasSuffix(mediaType, "/*") {
+			if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+				return fmt.Errorf("%q: %v", mediaType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMaxSize implements the maxsize tag, eg. maxsize=10Mi. The parameter is a resource.Quantity.
+// The field and the parameter are checked by checkMaxSize when the route is built.
+func validateMaxSize(ctx context.Context, fl validator.FieldLevel) bool {
+	fh := fileHeaderOf(fl)
+	max, err := resource.ParseQuantity(fl.Param())
+	return fh != nil && err == nil && fh.Size <= max.Value()
+}
+
+// validateContentType implements the contenttype tag, eg. contenttype=image/png image/jpeg or
+// contenttype=image/*. The content type is sniffed from the content of the file, the Content-Type
+// of the part is only used if it can't be detected. The field and the parameter are checked by
+// checkContentType when the route is built.
+func validateContentType(ctx context.Context, fl validator.FieldLevel) bool {
+	fh := fileHeaderOf(fl)
+	if fh == nil {
+		return false
+	}
+	contentType, err := sniffContentType(fh)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range strings.Fields(fl.Param()) {
+		if allowed == contentType || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(contentType, allowed[:len(allowed)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func sniffContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	contentType := http.DetectContentType(buf[:n])
+	if contentType == "application/octet-stream" {
+		contentType = fh.Header.Get("Content-Type")
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return mediaType, err
+}
+
+// fileHeaderOf returns the file header of the field of fl, or nil if it is not a file.
+func fileHeaderOf(fl validator.FieldLevel) *multipart.FileHeader {
+	if fn, ok := fl.Field().Interface().(fileHeaderFunc); ok {
+		return fn()
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/form/v4"
+	"github.com/go-playground/validator/v10"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	"github.com/tamalsaha/learn-chi/i18n"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// paramLocations are the struct tags used by Params, in the order they are decoded.
+var paramLocations = []string{"path", "query", "header", "cookie"}
+
+// paramDecoders decode the fields tagged with a location. Fields without the tag are skipped.
+var paramDecoders = func() map[string]*form.Decoder {
+	decoders := map[string]*form.Decoder{}
+	for _, loc := range paramLocations {
+		d := form.NewDecoder()
+		d.SetTagName(loc)
+		d.SetMode(form.ModeExplicit)
+		decoders[loc] = d
+	}
+	return decoders
+}()
+
+// paramField is a field of a Params struct.
+type paramField struct {
+	field    string // Go field name
+	location string
+	name     string // parameter name
+}
+
+// Params provides obj, a struct whose fields are bound to request parameters using the tags
+//
+//	path:"id"        chi URL parameter
+//	query:"name"     query string parameter
+//	header:"X-Foo"   request header
+//	cookie:"session" cookie value
+//
+// to the handlers of a router. The parameters are decoded with go-playground/form, so nested
+// query parameters like sort[0] are supported, the defaults of missing parameters are applied,
+// eg. `query:"limit" default:"10"`, and the struct is validated with the validator using its
+// validate tags. Only top level fields are bound to headers, cookies and path parameters.
+//
+// Like Provide, the struct is only decoded when a handler asks for it, so path parameters are
+// available even if Params is registered using r.Use. Decode and validation failures are written
+// as metav1.Status using errors.NewLocalizedBindingError in the language of the request. Unknown
+// query parameters are handled according to the WithFieldValidation level of the route.
+func Params(obj interface{}) func(next http.Handler) http.Handler {
+	ensureNotPointer(obj)
+	typ := reflect.TypeOf(obj)
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("binding: params must be a struct, found %s", typ))
+	}
+	fields := paramFields(typ)
+	if len(fields) == 0 {
+		panic(fmt.Sprintf("binding: params %s has no fields tagged with path, query, header or cookie", typ))
+	}
+
+	return bindProvider(typ, fmt.Sprintf("binding.Params(%s)", typ), func(w http.ResponseWriter, r *http.Request, ptr interface{}) error {
+		if err := bindParams(r, ptr, fields); err != nil {
+			return err
+		}
+		return checkUnknownFields(w, r, func() ([]string, error) {
+			return unknownQueryParams(r, fields), nil
+		})
+	})
+}
+
+// bindProvider returns the middleware providing values of typ bound by bind. bind is called
+// with a pointer to a new value of typ per request, its errors are converted into metav1.Status
+// by bindingError.
+func bindProvider(typ reflect.Type, name string, bind func(w http.ResponseWriter, r *http.Request, ptr interface{}) error) func(next http.Handler) http.Handler {
+	ctor := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{reflect.TypeOf((*http.ResponseWriter)(nil)).Elem(), reflect.TypeOf((*http.Request)(nil))}, []reflect.Type{typ, errorType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			w := args[0].Interface().(http.ResponseWriter)
+			r := args[1].Interface().(*http.Request)
+			v := reflect.New(typ)
+			if err := bind(w, r, v.Interface()); err != nil {
+				err = bindingError(err, v.Elem().Interface(), r)
+				return []reflect.Value{v.Elem(), reflect.ValueOf(err)}
+			}
+			return []reflect.Value{v.Elem(), reflect.Zero(errorType)}
+		})
+
+	p, err := newProvider(ctor.Interface(), PerRequest)
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+	return provide(p, name, validationRequires(typ)...)
+}
+
+// bindingError converts the error of binding obj into metav1.Status using
+// errors.NewLocalizedBindingError in the language of r. Errors of providers, eg. of the
+// dependencies of a validation, are not caused by the request and are returned as is, so that
+// their status is looked up along their unwrap chain and redacted in production mode.
+func bindingError(err error, obj interface{}, r *http.Request) error {
+	if errors.As(err, new(*ProviderError)) {
+		return err
+	}
+	return bindingerrors.NewLocalizedBindingError(err, obj, i18n.FromRequest(r))
+}
+
+func paramFields(typ reflect.Type) []paramField {
+	var fields []paramField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		for _, loc := range paramLocations {
+			if name := strings.Split(f.Tag.Get(loc), ",")[0]; name != "" && name != "-" {
+				fields = append(fields, paramField{field: f.Name, location: loc, name: name})
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// bindParams decodes the parameters of r into ptr and validates it.
+func bindParams(r *http.Request, ptr interface{}, fields []paramField) error {
+	var errs bindingerrors.ParamErrors
+	for _, loc := range paramLocations {
+		values := paramValues(r, loc, fields)
+		if len(values) == 0 {
+			continue
+		}
+		err := paramDecoders[loc].Decode(ptr, values)
+		if err == nil {
+			continue
+		}
+		decodeErrs, ok := err.(form.DecodeErrors)
+		if !ok {
+			return err
+		}
+		names := make([]string, 0, len(decodeErrs))
+		for name := range decodeErrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			errs = append(errs, bindingerrors.ParamError{Location: loc, Name: name, Type: metav1.CauseTypeFieldValueInvalid, Err: decodeErrs[name]})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if err := applyDefaults(ptr); err != nil {
+		return err
+	}
+
+	if err := validateRequest(r, ptr); err != nil {
+		validationErrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, fe := range validationErrs {
+			errs = append(errs, paramValidationError(fe, fields))
+		}
+		return errs
+	}
+	return nil
+}
+
+// paramValues returns the values of the parameters of r at location loc, keyed by parameter name.
+func paramValues(r *http.Request, loc string, fields []paramField) url.Values {
+	if loc == "query" {
+		return r.URL.Query() // keep nested keys, eg. sort[0]
+	}
+
+	values := url.Values{}
+	for _, f := range fields {
+		if f.location != loc {
+			continue
+		}
+		switch loc {
+		case "path":
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				for i, key := range rctx.URLParams.Keys {
+					if key == f.name {
+						values.Set(f.name, rctx.URLParams.Values[i])
+					}
+				}
+			}
+		case "header":
+			if vals := r.Header.Values(f.name); len(vals) > 0 {
+				values[f.name] = vals
+			}
+		case "cookie":
+			for _, c := range r.Cookies() {
+				if c.Name == f.name {
+					values.Add(f.name, c.Value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// unknownQueryParams returns the query parameters of r that are not bound to a field, named
+// query.name like the fields of ParamErrors. Nested keys are matched by their name, eg. sort[0] by sort.
+func unknownQueryParams(r *http.Request, fields []paramField) []string {
+	var unknown []string
+	for key := range r.URL.Query() {
+		name := key
+		if i := strings.IndexAny(key, ".["); i > 0 {
+			name = key[:i]
+		}
+		found := false
+		for _, f := range fields {
+			if f.location == "query" && f.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, "query."+key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// paramValidationError names the parameter of a validation error, eg. StructNamespace
+// Params.Tags[0] of the field tagged query:"tag" becomes the query parameter tag[0].
+func paramValidationError(fe validator.FieldError, fields []paramField) bindingerrors.ParamError {
+	ns := fe.StructNamespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		ns = ns[i+1:] // strip the struct name
+	}
+	field, rest := ns, ""
+	if i := strings.IndexAny(ns, ".["); i >= 0 {
+		field, rest = ns[:i], ns[i:]
+	}
+
+	e := bindingerrors.ParamError{Name: ns, Type: metav1.CauseTypeFieldValueInvalid, Err: fe}
+	if fe.Tag() == "required" {
+		e.Type = metav1.CauseTypeFieldValueRequired
+	}
+	for _, f := range fields {
+		if f.field == field {
+			e.Location, e.Name = f.location, f.name+rest
+			break
+		}
+	}
+	return e
+}
+
+// Don't pass in pointers to bind to. Can lead to bugs.
+func ensureNotPointer(obj interface{}) {
+	if reflect.TypeOf(obj).Kind() == reflect.Ptr {
+		panic("Pointers are not accepted as binding models")
+	}
+}
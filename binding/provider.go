@@ -0,0 +1,234 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	"go.wandrs.dev/inject"
+)
+
+// Lifetime controls how often a provider is called.
+type Lifetime int
+
+const (
+	// PerRequest providers are called at most once per request.
+	PerRequest Lifetime = iota
+	// Singleton providers are called once per process. Errors are not cached,
+	// so a failed singleton is retried by the next request that needs it.
+	Singleton
+)
+
+// provider constructs a value of typ on demand.
+type provider struct {
+	typ      reflect.Type
+	ctor     reflect.Value
+	lifetime Lifetime
+
+	mu    sync.Mutex
+	value reflect.Value
+}
+
+// providers are the providers registered along the middleware chain of a request.
+// They are stored in the request injector.
+type providers map[reflect.Type]*provider
+
+var providersType = reflect.TypeOf(providers(nil))
+
+func providersOf(injector inject.Injector) providers {
+	if v := injector.GetVal(providersType); v.IsValid() {
+		return v.Interface().(providers)
+	}
+	return nil
+}
+
+// newProvider validates ctor, which must have the signature func(deps...) T or func(deps...) (T, error).
+func newProvider(ctor interface{}, lifetime Lifetime) (*provider, error) {
+	typ := reflect.TypeOf(ctor)
+	if typ == nil || typ.Kind() != reflect.Func {
+		return nil, fmt.Errorf("provider must be a function, found %T", ctor)
+	}
+	switch typ.NumOut() {
+	case 1:
+	case 2:
+		if typ.Out(1) != errorType {
+			return nil, fmt.Errorf("2nd return value of provider %s must be error", typ)
+		}
+	default:
+		return nil, fmt.Errorf("provider %s must return T or (T, error)", typ)
+	}
+	if isErrorType(typ.Out(0)) {
+		return nil, fmt.Errorf("1st return value of provider %s must not be an error", typ)
+	}
+	return &provider{typ: typ.Out(0), ctor: reflect.ValueOf(ctor), lifetime: lifetime}, nil
+}
+
+// Provide registers ctor as the provider of its first return value T. ctor has the signature
+// func(deps...) T or func(deps...) (T, error) and is called the first time a handler or another
+// provider asks for T. Its arguments are resolved from the request injector, including other
+// providers. If ctor returns an error, the request fails with the error rendered as metav1.Status.
+// Values constructed by PerRequest providers that implement io.Closer are closed when the request completes.
+func Provide(ctor interface{}, lifetime Lifetime) func(next http.Handler) http.Handler {
+	p, err := newProvider(ctor, lifetime)
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+
+	return provide(p, fmt.Sprintf("binding.Provide(%s)", funcName(ctor)))
+}
+
+// provide returns the middleware that registers p for the request. The arguments of the
+// constructor of p and extra are declared as its requirements.
+func provide(p *provider, name string, extra ...reflect.Type) func(next http.Handler) http.Handler {
+	ctyp := p.ctor.Type()
+	requires := make([]reflect.Type, 0, ctyp.NumIn()+len(extra))
+	for i := 0; i < ctyp.NumIn(); i++ {
+		requires = append(requires, ctyp.In(i))
+	}
+	requires = append(requires, extra...)
+
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			ps := providersOf(injector)
+			if ps == nil {
+				ps = providers{}
+				injector.Map(ps)
+			}
+			ps[p.typ] = p
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: name, provides: []reflect.Type{p.typ}, requires: requires})
+}
+
+// ProviderError is returned when a provider fails to construct a value. It has no status of its
+// own: the response is the status of Err, found by unwrapping it, eg. a 404 Not Found
+// apierrors.StatusError or an error matched by the error registry of responsewriters, and a
+// 500 Internal Server Error otherwise.
+type ProviderError struct {
+	Type reflect.Type
+	Err  error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("failed to provide %s: %v", e.Type, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// resolve makes sure the arguments of a function of type typ that can be constructed by
+// providers of the request or its scope are mapped in the request injector. Arguments that are neither mapped nor provided are
+// left to inject.Injector.Invoke to report.
+func resolve(injector inject.Injector, typ reflect.Type) error {
+	ps, scope := providersOf(injector), scopeOf(injector)
+	if len(ps) == 0 && scope == nil {
+		return nil
+	}
+	for i := 0; i < typ.NumIn(); i++ {
+		if _, err := resolveType(injector, ps, scope, typ.In(i), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveType(injector inject.Injector, ps providers, scope *Scope, t reflect.Type, resolving []reflect.Type) (reflect.Value, error) {
+	if v := injector.GetVal(t); v.IsValid() {
+		return v, nil
+	}
+	p, ok := ps[t]
+	if !ok && scope != nil {
+		p, ok = scope.provider(t)
+	}
+	if !ok {
+		return reflect.Value{}, nil
+	}
+	for _, r := range resolving {
+		if r == t {
+			return reflect.Value{}, fmt.Errorf("binding: provider cycle detected for %s", t)
+		}
+	}
+	resolving = append(resolving, t)
+
+	v, err := p.get(func(ctyp reflect.Type) ([]reflect.Value, error) {
+		args := make([]reflect.Value, ctyp.NumIn())
+		for i := range args {
+			arg, err := resolveType(injector, ps, scope, ctyp.In(i), resolving)
+			if err != nil {
+				return nil, err
+			}
+			if !arg.IsValid() {
+				return nil, fmt.Errorf("binding: value not found for type %s required by provider of %s", ctyp.In(i), t)
+			}
+			args[i] = arg
+		}
+		return args, nil
+	})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if p.lifetime == Singleton {
+		untracked(injector).Set(t, v) // shared by all requests, must not be closed
+	} else {
+		injector.Set(t, v)
+	}
+	return v, nil
+}
+
+// get returns the value of the provider, calling the constructor if necessary.
+func (p *provider) get(args func(reflect.Type) ([]reflect.Value, error)) (reflect.Value, error) {
+	if p.lifetime == Singleton {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.value.IsValid() {
+			return p.value, nil
+		}
+	}
+
+	in, err := args(p.ctor.Type())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	out := p.ctor.Call(in)
+	if len(out) == 2 {
+		if err := toError(out[1]); err != nil {
+			return reflect.Value{}, &ProviderError{Type: p.typ, Err: responsewriters.WithStack(err)}
+		}
+	}
+
+	if p.lifetime == Singleton {
+		p.value = out[0]
+	}
+	return out[0], nil
+}
+
+// invoke calls fn with its arguments resolved from the injector and its providers.
+// Errors returned by providers are written to the response and reported by ok == false.
+func invoke(injector inject.Injector, fn interface{}, w http.ResponseWriter, r *http.Request) (results []reflect.Value, ok bool) {
+	if err := resolve(injector, reflect.TypeOf(fn)); err != nil {
+		handleResolveError(err, w, r)
+		return nil, false
+	}
+	results, err := injector.Invoke(fn)
+	if err != nil {
+		panic(fmt.Sprintf("failed to invoke %s, reason: %v", reflect.TypeOf(fn), err))
+	}
+	return results, true
+}
+
+// handleResolveError writes errors returned by providers to the response. Other errors,
+// eg. missing dependencies or provider cycles, are programming errors and panic.
+func handleResolveError(err error, w http.ResponseWriter, r *http.Request) {
+	if _, isProviderErr := err.(*ProviderError); !isProviderErr {
+		panic(err.Error())
+	}
+	writeError(err, w, r)
+}
@@ -0,0 +1,200 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/tamalsaha/learn-chi/negotiation"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	"go.wandrs.dev/inject"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// returnShape records the position of the status code, response value and error
+// in the return values of a handler. It is computed once when the handler is registered.
+// A negative index means the handler does not return that value.
+type returnShape struct {
+	status int
+	value  int
+	err    int
+}
+
+// newReturnShape validates the return values of a handler. The supported shapes are
+//
+//	func(...)
+//	func(...) error
+//	func(...) T
+//	func(...) (T, error)
+//	func(...) (int, T)
+//	func(...) (int, T, error)
+//
+// where error may be any type implementing error, eg. *CustomError.
+func newReturnShape(typ reflect.Type) (returnShape, error) {
+	shape := returnShape{status: -1, value: -1, err: -1}
+
+	switch typ.NumOut() {
+	case 0:
+		// nothing returned, assuming function directly writes to http.ResponseWriter
+	case 1:
+		if isErrorType(typ.Out(0)) {
+			shape.err = 0
+		} else {
+			shape.value = 0
+		}
+	case 2:
+		if isErrorType(typ.Out(1)) {
+			shape.value, shape.err = 0, 1
+		} else if typ.Out(0).Kind() == reflect.Int {
+			shape.status, shape.value = 0, 1
+		} else if err := checkErrorType(typ.Out(1)); err != nil {
+			return shape, err
+		} else {
+			return shape, fmt.Errorf("2nd return value %s must implement error or 1st return value must be an int status code", typ.Out(1))
+		}
+	case 3:
+		if typ.Out(0).Kind() != reflect.Int {
+			return shape, fmt.Errorf("1st return value %s must be an int status code", typ.Out(0))
+		}
+		if err := checkErrorType(typ.Out(2)); err != nil {
+			return shape, err
+		}
+		shape.status, shape.value, shape.err = 0, 1, 2
+	default:
+		return shape, fmt.Errorf("has %d return values, at most 3 are allowed", typ.NumOut())
+	}
+
+	if shape.value >= 0 {
+		if err := checkValueType(typ.Out(shape.value)); err != nil {
+			return shape, err
+		}
+	}
+	return shape, nil
+}
+
+func isErrorType(t reflect.Type) bool {
+	return t.Implements(errorType)
+}
+
+func checkErrorType(t reflect.Type) error {
+	if isErrorType(t) {
+		return nil
+	}
+	if reflect.PtrTo(t).Implements(errorType) {
+		return fmt.Errorf("return type should be *%s to be considered an error", t)
+	}
+	return fmt.Errorf("return type %s must implement error", t)
+}
+
+func checkValueType(t reflect.Type) error {
+	if isErrorType(t) {
+		return fmt.Errorf("return type %s must not be an error in this position", t)
+	}
+	if reflect.PtrTo(t).Implements(errorType) {
+		return fmt.Errorf("return type should be *%s to be considered an error", t)
+	}
+	return nil
+}
+
+// write renders the results of a handler call according to the return shape.
+// A non nil error always takes precedence over the status code and response value.
+func (s returnShape) write(w http.ResponseWriter, r *http.Request, results []reflect.Value) {
+	if s.err >= 0 {
+		if err := toError(results[s.err]); err != nil {
+			writeError(responsewriters.WithStack(err), w, r)
+			return
+		}
+	}
+	if s.value < 0 {
+		return
+	}
+
+	code := http.StatusOK
+	if s.status >= 0 {
+		code = int(results[s.status].Int())
+	}
+	writeValue(w, r, code, results[s.value])
+}
+
+func toError(v reflect.Value) error {
+	if canDeref(v) && v.IsNil() {
+		return nil // typed nil, eg. (*CustomError)(nil)
+	}
+	return v.Interface().(error)
+}
+
+// writeValue writes v to the response. []byte is written as is, everything else is written
+// in the media type negotiated with the client. nil slices and maps are written as empty
+// arrays and objects. A nil pointer or interface results in an empty response.
+func writeValue(w http.ResponseWriter, r *http.Request, code int, v reflect.Value) {
+	for canDeref(v) {
+		if v.IsNil() {
+			if code == http.StatusOK {
+				code = http.StatusNoContent
+			}
+			w.WriteHeader(code)
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case isByteSlice(v):
+		w.WriteHeader(code)
+		_, _ = w.Write(v.Bytes())
+	case v.Kind() == reflect.Slice && v.IsNil():
+		responsewriters.WriteObjectNegotiated(negotiation.Default, code, reflect.MakeSlice(v.Type(), 0, 0).Interface(), w, r)
+	case v.Kind() == reflect.Map && v.IsNil():
+		responsewriters.WriteObjectNegotiated(negotiation.Default, code, reflect.MakeMap(v.Type()).Interface(), w, r)
+	default:
+		responsewriters.WriteObjectNegotiated(negotiation.Default, code, v.Interface(), w, r)
+	}
+}
+
+// Handler converts a Macaron style handler into a http.HandlerFunc. The arguments of fn
+// are resolved from the request injector and its providers and its return values are written
+// to the response.
+// Handler panics if fn is not a function or its return values are not supported.
+// Use Verify to check that all arguments of fn are provided when the server starts.
+// If an inject.FastInvoker adapter generated by cmd/fastinvoker-gen is registered for the
+// type of fn, fn is called without reflection.
+//
+// github.com/go-macaron/macaron/return_handler.go
+func Handler(fn interface{}) http.HandlerFunc {
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func {
+		panic(fmt.Sprintf("binding: handler must be a function, found %T", fn))
+	}
+	shape, err := newReturnShape(typ)
+	if err != nil {
+		panic(fmt.Sprintf("binding: handler %s %v", typ, err))
+	}
+
+	requires := make([]reflect.Type, 0, typ.NumIn())
+	for i := 0; i < typ.NumIn(); i++ {
+		requires = append(requires, typ.In(i))
+	}
+	name := funcName(fn)
+	fn = fastInvoker(fn)
+
+	return declareHandler(func(w http.ResponseWriter, r *http.Request) {
+		injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+		if injector == nil {
+			panic("chi: register Injector middleware")
+		}
+		results, ok := invoke(injector, fn, w, r)
+		if !ok {
+			return
+		}
+		shape.write(w, r, results)
+	}, declaration{name: name, requires: requires})
+}
+
+func canDeref(val reflect.Value) bool {
+	return val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr
+}
+
+func isByteSlice(val reflect.Value) bool {
+	return val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8
+}
@@ -0,0 +1,134 @@
+package binding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"go.wandrs.dev/inject"
+)
+
+// Scope is an injector shared by all requests served by a router. The application scope
+// is created once at startup and route scopes are created for sub routers (r.Route, r.Group)
+// with the application scope as their parent. Request injectors look up dependencies in
+// the request first, then in the route scope and finally in the application scope.
+//
+// Scopes must be fully populated before the server starts, as they are read concurrently
+// by all requests.
+type Scope struct {
+	injector  inject.Injector
+	parent    *Scope
+	types     []reflect.Type
+	providers providers
+}
+
+var scopeType = reflect.TypeOf((*Scope)(nil))
+
+// NewScope returns a new Scope. parent is nil for the application scope.
+func NewScope(parent *Scope) *Scope {
+	s := &Scope{
+		injector:  inject.New(),
+		parent:    parent,
+		providers: providers{},
+	}
+	if parent != nil {
+		s.injector.SetParent(parent.injector)
+	}
+	return s
+}
+
+// Map maps val based on its immediate type from reflect.TypeOf.
+func (s *Scope) Map(val interface{}) *Scope {
+	s.injector.Map(val)
+	s.types = append(s.types, reflect.TypeOf(val))
+	return s
+}
+
+// MapTo maps val based on the pointer of an Interface provided.
+func (s *Scope) MapTo(val interface{}, ifacePtr interface{}) *Scope {
+	s.injector.MapTo(val, ifacePtr)
+	s.types = append(s.types, inject.InterfaceOf(ifacePtr))
+	return s
+}
+
+// Set maps val to typ.
+func (s *Scope) Set(typ reflect.Type, val reflect.Value) *Scope {
+	s.injector.Set(typ, val)
+	s.types = append(s.types, typ)
+	return s
+}
+
+// Provide registers ctor as the provider of its first return value for all requests in the scope.
+// See Provide for the supported signatures of ctor.
+func (s *Scope) Provide(ctor interface{}, lifetime Lifetime) *Scope {
+	p, err := newProvider(ctor, lifetime)
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+	s.providers[p.typ] = p
+	s.types = append(s.types, p.typ)
+	return s
+}
+
+// GetVal returns the value mapped to t in the scope or its parents.
+func (s *Scope) GetVal(t reflect.Type) reflect.Value {
+	return s.injector.GetVal(t)
+}
+
+// Types returns the types mapped or provided by the scope and its parents.
+func (s *Scope) Types() []reflect.Type {
+	var types []reflect.Type
+	for cur := s; cur != nil; cur = cur.parent {
+		types = append(types, cur.types...)
+	}
+	return types
+}
+
+// provider returns the provider of t registered in the scope or its parents.
+func (s *Scope) provider(t reflect.Type) (*provider, bool) {
+	for cur := s; cur != nil; cur = cur.parent {
+		if p, ok := cur.providers[t]; ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Scope) requires() []reflect.Type {
+	var types []reflect.Type
+	for cur := s; cur != nil; cur = cur.parent {
+		for _, p := range cur.providers {
+			ctyp := p.ctor.Type()
+			for i := 0; i < ctyp.NumIn(); i++ {
+				types = append(types, ctyp.In(i))
+			}
+		}
+	}
+	return types
+}
+
+// WithScope makes the request injector a child of s, so that the dependencies mapped in s
+// are available to the handlers of a router. A WithScope middleware overrides the scope set
+// by WithScope middlewares in front of it, so route scopes should use the application scope
+// as their parent.
+func WithScope(s *Scope) func(next http.Handler) http.Handler {
+	return declareMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			injector, _ := r.Context().Value(injectorKey{}).(inject.Injector)
+			if injector == nil {
+				panic("chi: register Injector middleware")
+			}
+
+			injector.SetParent(s.injector)
+			injector.Map(s)
+			next.ServeHTTP(w, r)
+		})
+	}, declaration{name: fmt.Sprintf("binding.WithScope(%p)", s), scope: s})
+}
+
+func scopeOf(injector inject.Injector) *Scope {
+	if v := injector.GetVal(scopeType); v.IsValid() {
+		return v.Interface().(*Scope)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package binding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ndjsonMediaTypes are the media types of newline-delimited JSON.
+var ndjsonMediaTypes = []string{"application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines"}
+
+// JSONStream provides *Items to the handlers of a router, an iterator over the items of type
+// obj of a request body holding either a top level JSON array or, if its Content-Type is
+// application/x-ndjson, newline-delimited JSON. Items are decoded one at a time, so bulk
+// imports don't need to keep the whole body in memory:
+//
+//	func importUsers(items *binding.Items) error {
+//		var u User
+//		for items.Next(&u) {
+//			...
+//		}
+//		return items.Err()
+//	}
+//
+// Like JSON, the defaults of every item are applied, it is validated and unknown fields are
+// handled according to the WithFieldValidation level of the route. Items that fail are skipped
+// and reported together by Items.Err. The size of the body is limited using WithMaxBodySize.
+func JSONStream(obj interface{}) func(next http.Handler) http.Handler {
+	ensureNotPointer(obj)
+	typ := reflect.TypeOf(obj)
+	p, err := newProvider(func(w http.ResponseWriter, r *http.Request) (*Items, error) {
+		return newItems(w, r, typ)
+	}, PerRequest)
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+	return provide(p, fmt.Sprintf("binding.JSONStream(%s)", typ), validationRequires(typ)...)
+}
+
+// Items iterates over the items of a JSON array or newline-delimited JSON request body.
+// It is provided by JSONStream.
+type Items struct {
+	w     http.ResponseWriter
+	r     *http.Request
+	typ   reflect.Type
+	limit int64
+
+	dec   *json.Decoder // JSON arrays
+	lines *bufio.Reader // newline-delimited JSON
+	line  int           // lines read from lines
+
+	started, done bool
+	index         int // index of the next item
+	errs          bindingerrors.ItemErrors
+	err           error // the error that ended the iteration early
+}
+
+func newItems(w http.ResponseWriter, r *http.Request, typ reflect.Type) (*Items, error) {
+	it := &Items{w: w, r: r, typ: typ, index: -1, limit: maxBodySizeOf(r)}
+	limitBody(r, it.limit)
+	body := r.Body
+	if body == nil {
+		body = http.NoBody
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case isNDJSON(mediaType):
+		it.lines = bufio.NewReader(body)
+	case mediaType == "", mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		it.dec = json.NewDecoder(body)
+	default:
+		return nil, unsupportedMediaType(mediaType, append([]string{"application/json"}, ndjsonMediaTypes...))
+	}
+	return it, nil
+}
+
+func isNDJSON(mediaType string) bool {
+	for _, t := range ndjsonMediaTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Next decodes the next valid item into the object ptr points to, which must be of the type
+// passed to JSONStream. Items that can't be decoded or fail validation are skipped. It returns
+// false when there are no more items or the body can't be read any further, eg. because of a
+// syntax error of a JSON array.
+func (it *Items) Next(ptr interface{}) bool {
+	if t := reflect.TypeOf(ptr); t != reflect.PtrTo(it.typ) {
+		panic(fmt.Sprintf("binding: Items.Next requires a *%s, found %s", it.typ, t))
+	}
+
+	for !it.done {
+		raw, err := it.next()
+		if err != nil {
+			it.done, it.err = true, err
+			if errors.Is(err, errBodyTooLarge) {
+				it.err = bodyTooLarge(it.limit)
+			}
+			return false
+		}
+		if raw == nil {
+			it.done = true
+			return false
+		}
+
+		it.index++
+		v := reflect.New(it.typ)
+		if err := it.bind(raw, v.Interface()); err != nil {
+			if _, isAPIStatus := err.(apierrors.APIStatus); isAPIStatus || errors.As(err, new(*ProviderError)) {
+				it.done, it.err = true, err // eg. a provider of a validation failed
+				return false
+			}
+			it.errs = append(it.errs, &bindingerrors.ItemError{Index: it.index, Err: err})
+			continue
+		}
+		reflect.ValueOf(ptr).Elem().Set(v.Elem())
+		return true
+	}
+	return false
+}
+
+// Index returns the 0-based index of the item last decoded by Next in the body, counting the
+// items that were skipped.
+func (it *Items) Index() int {
+	return it.index
+}
+
+// Err returns the error of the body as *apierrors.StatusError once Next returned false, or nil
+// if every item is valid. Errors that ended the iteration early, eg. a body that is not a JSON
+// array, has data after the array or is larger than the limit of WithMaxBodySize, are returned
+// as is. Otherwise the errors of
+// the skipped items are returned together, with causes whose fields are prefixed with the index
+// of the item, eg. [3].name.
+func (it *Items) Err() error {
+	obj := reflect.New(it.typ).Elem().Interface()
+	if it.err != nil {
+		return bindingError(it.err, obj, it.r)
+	}
+	if len(it.errs) > 0 {
+		return bindingError(it.errs, obj, it.r)
+	}
+	return nil
+}
+
+// next returns the JSON of the next item, or nil at the end of the body.
+func (it *Items) next() (json.RawMessage, error) {
+	if it.lines != nil {
+		return it.nextLine()
+	}
+
+	if !it.started {
+		it.started = true
+		tok, err := it.dec.Token()
+		if err == io.EOF {
+			return nil, &bindingerrors.JSONError{Err: bindingerrors.ErrEmptyBody}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tok != json.Delim('[') {
+			return nil, &bindingerrors.SyntaxError{Format: "JSON array", Err: fmt.Errorf("expected [, found %v", tok)}
+		}
+	}
+	if !it.dec.More() {
+		if _, err := it.dec.Token(); err != nil { // the closing ]
+			return nil, err
+		}
+		// the array must be the whole body
+		if tok, err := it.dec.Token(); err != io.EOF {
+			if err != nil {
+				return nil, err
+			}
+			return nil, &bindingerrors.SyntaxError{Format: "JSON array", Err: fmt.Errorf("unexpected %v after the array", tok)}
+		}
+		return nil, nil
+	}
+	var raw json.RawMessage
+	if err := it.dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// nextLine returns the next line of newline-delimited JSON that is not blank.
+func (it *Items) nextLine() (json.RawMessage, error) {
+	for {
+		line, err := it.lines.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) > 0 {
+			it.line++
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			return line, nil
+		}
+		if err == io.EOF {
+			return nil, nil
+		}
+	}
+}
+
+// bind decodes the item raw into ptr and validates it.
+func (it *Items) bind(raw json.RawMessage, ptr interface{}) error {
+	if err := json.Unmarshal(raw, ptr); err != nil {
+		if it.lines == nil {
+			return err // positions within an item of an array are of no use
+		}
+		err = bindingerrors.NewJSONError(err, raw)
+		if e, ok := err.(*bindingerrors.JSONError); ok && e.Line > 0 {
+			e.Line = it.line // lines of newline-delimited JSON are single line items
+		}
+		return err
+	}
+
+	prefix := fmt.Sprintf("[%d].", it.index)
+	err := checkUnknownFields(it.w, it.r, func() ([]string, error) {
+		fields, err := bindingerrors.UnknownJSONFields(raw, ptr)
+		for i := range fields {
+			fields[i] = prefix + fields[i] // warnings name the item
+		}
+		return fields, err
+	})
+	if ue, ok := err.(*bindingerrors.UnknownFieldsError); ok {
+		for i := range ue.Fields {
+			ue.Fields[i] = strings.TrimPrefix(ue.Fields[i], prefix) // the item is named by ItemErrors
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := applyDefaults(ptr); err != nil {
+		return err
+	}
+	return validateRequest(it.r, ptr)
+}
@@ -0,0 +1,68 @@
+package binding_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/unrolled/render"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type streamItem struct {
+	Name string `json:"name"`
+}
+
+// TestJSONStreamArray checks that a JSON array must be the whole body.
+func TestJSONStreamArray(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"array", `[{"name": "a"}, {"name": "b"}]`, http.StatusOK},
+		{"trailing whitespace", "[{\"name\": \"a\"}]\n\t ", http.StatusOK},
+		{"empty array", `[]`, http.StatusOK},
+		{"trailing object", `[{"name": "a"}] {"x": 1}`, http.StatusBadRequest},
+		{"trailing array", `[{"name": "a"}][]`, http.StatusBadRequest},
+		{"trailing garbage", `[{"name": "a"}] x`, http.StatusBadRequest},
+		{"not an array", `{"name": "a"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(binding.Injector(render.New()))
+			r.With(binding.JSONStream(streamItem{})).Post("/", binding.Handler(func(items *binding.Items) (int, error) {
+				var item streamItem
+				n := 0
+				for items.Next(&item) {
+					n++
+				}
+				return n, items.Err()
+			}))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, jsonRequest(c.body))
+			if w.Code != c.code {
+				t.Fatalf("got %d, want %d: %s", w.Code, c.code, w.Body)
+			}
+			if c.code == http.StatusOK {
+				return
+			}
+			var status metav1.Status
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("%v: %s", err, w.Body)
+			}
+			if status.Reason != metav1.StatusReasonBadRequest || status.Details == nil || len(status.Details.Causes) == 0 {
+				t.Errorf("got %s", w.Body)
+			}
+			if strings.Contains(c.name, "trailing") && !strings.Contains(w.Body.String(), "after the array") && !strings.Contains(w.Body.String(), "invalid character") {
+				t.Errorf("got %s", w.Body)
+			}
+		})
+	}
+}
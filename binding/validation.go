@@ -0,0 +1,269 @@
+package binding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
+	"go.wandrs.dev/inject"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(bindingerrors.TagNameFunc(bindingerrors.FieldPathTag))
+	return v
+}()
+
+var (
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	fieldLevelType  = reflect.TypeOf((*validator.FieldLevel)(nil)).Elem()
+	structLevelType = reflect.TypeOf((*validator.StructLevel)(nil)).Elem()
+)
+
+// validation is a custom validation registered with RegisterValidation or RegisterStructValidation.
+type validation struct {
+	fn   reflect.Value
+	deps []reflect.Type
+}
+
+// paramCheck checks the parameter of a validator tag and the type t of the field, or of its
+// elements after dive, the tag is used on.
+type paramCheck func(t reflect.Type, param string) error
+
+var validations = struct {
+	sync.RWMutex
+	tags    map[string]*validation
+	structs map[reflect.Type]*validation
+	checks  map[string]paramCheck
+}{tags: map[string]*validation{}, structs: map[reflect.Type]*validation{}, checks: map[string]paramCheck{}}
+
+// registerParamCheck registers check for the validator tag, so that misused tags fail when the
+// routes using them are built instead of on every request.
+func registerParamCheck(tag string, check paramCheck) {
+	validations.Lock()
+	defer validations.Unlock()
+	validations.checks[tag] = check
+}
+
+// RegisterValidation registers fn as the validation of the validator tag. fn has the signature
+//
+//	func(ctx context.Context, fl validator.FieldLevel, deps...) bool
+//
+// ctx is the context of the request and deps are resolved from the request injector like the
+// arguments of a handler, including providers and scopes, eg. a kubernetes.Interface to check
+// that a referenced namespace exists. Failures are reported like the failures of built-in tags,
+// with the message registered for the tag in the i18n catalog.
+//
+// The validator.Validate shared by all binders can't register validations while it validates
+// requests, so validations must be registered before requests are served. They must also be
+// registered before the routes using them are built, so that Verify checks their dependencies.
+func RegisterValidation(tag string, fn interface{}, callValidationEvenIfNull ...bool) {
+	v := newValidation(fn, fieldLevelType)
+	err := validate.RegisterValidationCtx(tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		args, ok := v.args(ctx, reflect.ValueOf(fl))
+		if !ok {
+			return true // the failure is reported by validateRequest
+		}
+		return v.fn.Call(args)[0].Bool()
+	}, callValidationEvenIfNull...)
+	if err != nil {
+		panic("binding: " + err.Error())
+	}
+
+	validations.Lock()
+	defer validations.Unlock()
+	validations.tags[tag] = v
+}
+
+// RegisterStructValidation registers fn as the struct level validation of the types of objs.
+// fn has the signature
+//
+//	func(ctx context.Context, sl validator.StructLevel, deps...)
+//
+// and reports failures using sl.ReportError. Its dependencies are resolved like the ones of
+// RegisterValidation.
+func RegisterStructValidation(fn interface{}, objs ...interface{}) {
+	v := newValidation(fn, structLevelType)
+	validate.RegisterStructValidationCtx(func(ctx context.Context, sl validator.StructLevel) {
+		if args, ok := v.args(ctx, reflect.ValueOf(sl)); ok {
+			v.fn.Call(args)
+		}
+	}, objs...)
+
+	validations.Lock()
+	defer validations.Unlock()
+	for _, obj := range objs {
+		validations.structs[reflect.TypeOf(obj)] = v
+	}
+}
+
+func newValidation(fn interface{}, levelType reflect.Type) *validation {
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func || typ.NumIn() < 2 || typ.In(0) != contextType || typ.In(1) != levelType {
+		panic(fmt.Sprintf("binding: validation must be a func(context.Context, %s, deps...), found %T", levelType, fn))
+	}
+	if levelType == fieldLevelType && (typ.NumOut() != 1 || typ.Out(0).Kind() != reflect.Bool) {
+		panic(fmt.Sprintf("binding: validation %s must return bool", typ))
+	}
+	if levelType == structLevelType && typ.NumOut() != 0 {
+		panic(fmt.Sprintf("binding: struct validation %s must not return values", typ))
+	}
+
+	v := &validation{fn: reflect.ValueOf(fn)}
+	for i := 2; i < typ.NumIn(); i++ {
+		v.deps = append(v.deps, typ.In(i))
+	}
+	return v
+}
+
+// validationStateKey is the context key of the validationState of validateRequest.
+type validationStateKey struct{}
+
+// validationState records the first error resolving the dependencies of a validation,
+// since validator funcs can't return errors.
+type validationState struct {
+	err error
+}
+
+// args returns the arguments of the validation. ok is false if a dependency can't be resolved.
+func (v *validation) args(ctx context.Context, level reflect.Value) (args []reflect.Value, ok bool) {
+	args = append(make([]reflect.Value, 0, 2+len(v.deps)), reflect.ValueOf(&ctx).Elem(), level)
+	if len(v.deps) == 0 {
+		return args, true
+	}
+
+	st, _ := ctx.Value(validationStateKey{}).(*validationState)
+	if st == nil {
+		st = &validationState{} // not validated by binding, eg. validate.Struct
+	}
+	if st.err != nil {
+		return nil, false
+	}
+	injector, _ := ctx.Value(injectorKey{}).(inject.Injector)
+	if injector == nil {
+		st.err = fmt.Errorf("binding: validation %s requires the Injector middleware", v.fn.Type())
+		return nil, false
+	}
+	ps, scope := providersOf(injector), scopeOf(injector)
+	for _, t := range v.deps {
+		arg, err := resolveType(injector, ps, scope, t, nil)
+		if err == nil && !arg.IsValid() {
+			err = fmt.Errorf("binding: value not found for type %s required by validation %s", t, v.fn.Type())
+		}
+		if err != nil {
+			st.err = err
+			return nil, false
+		}
+		args = append(args, arg)
+	}
+	return args, true
+}
+
+// validateRequest validates ptr, if it points to a struct, using the context of r, so that
+// custom validations can resolve their dependencies from the request injector. Errors of
+// providers are returned as is, other errors resolving dependencies are bugs in the source
+// code and result in 500 Internal Server Error.
+func validateRequest(r *http.Request, ptr interface{}) error {
+	if reflect.TypeOf(ptr).Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	st := &validationState{}
+	err := validate.StructCtx(context.WithValue(r.Context(), validationStateKey{}, st), ptr)
+	if st.err != nil {
+		if _, isProviderErr := st.err.(*ProviderError); isProviderErr {
+			return st.err
+		}
+		return apierrors.NewInternalError(st.err)
+	}
+	return err
+}
+
+// validationRequires returns the dependencies of the custom validations used by the struct
+// type typ, its nested structs and their validate tags. Verify checks them. It panics if a tag
+// is used with an invalid parameter or on a field of the wrong type, see registerParamCheck.
+func validationRequires(typ reflect.Type) []reflect.Type {
+	validations.RLock()
+	defer validations.RUnlock()
+
+	var requires []reflect.Type
+	seen := map[reflect.Type]bool{}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || seen[t] {
+			return
+		}
+		seen[t] = true
+		if v, ok := validations.structs[t]; ok {
+			requires = append(requires, v.deps...)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			fld := t.Field(i)
+			if err := checkValidationParams(fld); err != nil {
+				panic(fmt.Sprintf("binding: field %s of %s: %v", fld.Name, t, err))
+			}
+			for _, tag := range validationTags(fld.Tag.Get("validate")) {
+				if v, ok := validations.tags[tag]; ok {
+					requires = append(requires, v.deps...)
+				}
+			}
+			walk(fld.Type)
+		}
+	}
+	walk(typ)
+	return requires
+}
+
+// checkValidationParams checks the parameters of the validate tag of fld. Tags after dive are
+// checked against the type of the elements of the field. validations must be locked.
+func checkValidationParams(fld reflect.StructField) error {
+	t := fld.Type
+	for _, part := range strings.Split(fld.Tag.Get("validate"), ",") {
+		if part == "dive" {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+			continue
+		}
+		for _, tag := range strings.Split(part, "|") {
+			param := ""
+			if i := strings.IndexByte(tag, '='); i >= 0 {
+				tag, param = tag[:i], tag[i+1:]
+			}
+			if check, ok := validations.checks[tag]; ok {
+				if err := check(t, param); err != nil {
+					return fmt.Errorf("invalid %s: %v", tag, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validationTags returns the names of the tags of a validate struct tag, eg. required and
+// oneof for required,oneof=a b.
+func validationTags(s string) []string {
+	var tags []string
+	for _, or := range strings.Split(s, ",") {
+		for _, tag := range strings.Split(or, "|") {
+			if i := strings.IndexByte(tag, '='); i >= 0 {
+				tag = tag[:i]
+			}
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
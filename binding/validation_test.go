@@ -0,0 +1,113 @@
+package binding_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	"github.com/unrolled/render"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// namespaces is the dependency of the namespace_known validation.
+type namespaces interface {
+	Exists(name string) bool
+}
+
+type namespaceParams struct {
+	Namespace string `query:"ns" json:"ns" validate:"namespace_known"`
+}
+
+func init() {
+	binding.RegisterValidation("namespace_known", func(ctx context.Context, fl validator.FieldLevel, ns namespaces) bool {
+		return ns.Exists(fl.Field().String())
+	})
+}
+
+// TestValidationProviderError checks that a provider failing inside a validation is reported
+// with the status of its error, not as a bad request, and redacted in production mode.
+func TestValidationProviderError(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "kubeconfig")
+	cases := []struct {
+		name       string
+		err        error
+		production bool
+		code       int32
+		reason     metav1.StatusReason
+		message    string
+	}{
+		{"plain error", errors.New("load /secret/kubeconfig: invalid configuration"), false, http.StatusInternalServerError, metav1.StatusReasonUnknown, "/secret/kubeconfig"},
+		{"plain error redacted", errors.New("load /secret/kubeconfig: invalid configuration"), true, http.StatusInternalServerError, metav1.StatusReasonUnknown, "Internal Server Error"},
+		{"API error", fmt.Errorf("load kubeconfig: %w", notFound), false, http.StatusNotFound, metav1.StatusReasonNotFound, "kubeconfig"},
+	}
+	binders := []struct {
+		name    string
+		binder  func(obj interface{}) func(next http.Handler) http.Handler
+		handler interface{}
+		request func() *http.Request
+	}{
+		{"Params", binding.Params, func(p namespaceParams) string { return p.Namespace }, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/?ns=default", nil)
+		}},
+		{"JSON", binding.JSON, func(p namespaceParams) string { return p.Namespace }, func() *http.Request {
+			return jsonRequest(`{"ns": "default"}`)
+		}},
+		{"JSONStream", binding.JSONStream, func(items *binding.Items) error {
+			var p namespaceParams
+			for items.Next(&p) {
+			}
+			return items.Err()
+		}, func() *http.Request {
+			return jsonRequest(`[{"ns": "default"}]`)
+		}},
+	}
+
+	defer func(production bool) { responsewriters.ProductionMode = production }(responsewriters.ProductionMode)
+	for _, c := range cases {
+		for _, b := range binders {
+			t.Run(c.name+"/"+b.name, func(t *testing.T) {
+				responsewriters.ProductionMode = c.production
+				err := c.err
+				r := chi.NewRouter()
+				r.Use(binding.Injector(render.New()))
+				r.Use(binding.Provide(func() (namespaces, error) { return nil, err }, binding.PerRequest))
+				r.With(b.binder(namespaceParams{})).Post("/", binding.Handler(b.handler))
+				r.With(b.binder(namespaceParams{})).Get("/", binding.Handler(b.handler))
+
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, b.request())
+
+				var status metav1.Status
+				if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+					t.Fatalf("%v: %s", err, w.Body)
+				}
+				if int32(w.Code) != c.code || status.Code != c.code || status.Reason != c.reason {
+					t.Errorf("got %d %s, want %d %s: %s", w.Code, status.Reason, c.code, c.reason, w.Body)
+				}
+				if !strings.Contains(status.Message, c.message) {
+					t.Errorf("got message %q, want it to contain %q", status.Message, c.message)
+				}
+				if c.production && strings.Contains(status.Message, "/secret") {
+					t.Errorf("message %q is not redacted", status.Message)
+				}
+			})
+		}
+	}
+}
+
+func jsonRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
@@ -0,0 +1,68 @@
+package binding
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValueDecoder decodes the string value of a parameter or form field into a value of the type
+// it is registered for with RegisterValueDecoder.
+type ValueDecoder func(value string) (interface{}, error)
+
+// RegisterValueDecoder registers fn as the decoder of the values of the types of objs, eg.
+//
+//	binding.RegisterValueDecoder(func(s string) (interface{}, error) {
+//		return uuid.Parse(s)
+//	}, uuid.UUID{})
+//
+// so that fields of these types, pointers to them and slices of them can be bound to path, query,
+// header and cookie parameters by Params and to form fields by Form and Bind. Decoders of the
+// following types are registered by default:
+//
+//	time.Duration       time.ParseDuration, eg. 1h30m
+//	metav1.Time         RFC 3339, like the query parameters of the kube-apiserver
+//	resource.Quantity   resource.ParseQuantity, eg. 500Mi
+//	ulid.ULID           ulid.ParseStrict
+//	net.IP              net.ParseIP
+//
+// form.Decoder.RegisterCustomTypeFunc isn't safe to call while the decoders of Params, Form and
+// Bind decode requests, so value decoders must be registered before requests are served.
+// Registering a decoder for a type again replaces it.
+func RegisterValueDecoder(fn ValueDecoder, objs ...interface{}) {
+	decode := func(vals []string) (interface{}, error) {
+		return fn(vals[0])
+	}
+	formDecoder.RegisterCustomTypeFunc(decode, objs...)
+	for _, d := range paramDecoders {
+		d.RegisterCustomTypeFunc(decode, objs...)
+	}
+}
+
+func init() {
+	RegisterValueDecoder(func(s string) (interface{}, error) {
+		return time.ParseDuration(s)
+	}, time.Duration(0))
+	RegisterValueDecoder(func(s string) (interface{}, error) {
+		var t metav1.Time
+		err := t.UnmarshalQueryParameter(s)
+		return t, err
+	}, metav1.Time{})
+	RegisterValueDecoder(func(s string) (interface{}, error) {
+		return resource.ParseQuantity(s)
+	}, resource.Quantity{})
+	RegisterValueDecoder(func(s string) (interface{}, error) {
+		return ulid.ParseStrict(s)
+	}, ulid.ULID{})
+	RegisterValueDecoder(func(s string) (interface{}, error) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", s)
+		}
+		return ip, nil
+	}, net.IP{})
+}
@@ -0,0 +1,266 @@
+package binding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	httpw "go.wandrs.dev/http"
+)
+
+// declaration records the types a middleware provides to the injector or the types
+// a handler requires from it. It is used by Verify to check the dependencies of every
+// route when the server starts.
+type declaration struct {
+	name     string
+	injector bool
+	scope    *Scope // types of a scope are only known once all routes are registered
+	provides []reflect.Type
+	requires []reflect.Type
+}
+
+// injectorTypes are the types mapped by the Injector middleware for every request.
+var injectorTypes = []reflect.Type{
+	reflect.TypeOf((*context.Context)(nil)).Elem(),
+	reflect.TypeOf((*http.Request)(nil)),
+	reflect.TypeOf((*http.ResponseWriter)(nil)).Elem(),
+	reflect.TypeOf((*httpw.ResponseWriter)(nil)).Elem(),
+}
+
+// probe collects the declaration of a middleware or handler of this package. Verify passes it
+// as the next handler to middlewares and in the context of a request to handlers, which store
+// their declaration in it instead of doing anything else.
+type probe struct {
+	d *declaration
+}
+
+func (*probe) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+type probeKey struct{}
+
+// declareMiddleware returns mw, answering the probes of Verify with d. Every declared middleware
+// is a closure of the same func literal, so that Verify only probes middlewares of this package.
+//
+//go:noinline
+func declareMiddleware(mw func(http.Handler) http.Handler, d declaration) func(http.Handler) http.Handler {
+	if d.name == "" {
+		d.name = funcName(mw)
+	}
+	return func(next http.Handler) http.Handler {
+		if p, ok := next.(*probe); ok {
+			p.d = &d
+			return p
+		}
+		return mw(next)
+	}
+}
+
+// declareHandler returns h, answering the probes of Verify with d, see declareMiddleware.
+//
+//go:noinline
+func declareHandler(h http.HandlerFunc, d declaration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := r.Context().Value(probeKey{}).(*probe); ok {
+			p.d = &d
+			return
+		}
+		h(w, r)
+	}
+}
+
+// The code pointers of the closures returned by declareMiddleware and declareHandler.
+var (
+	declaredMiddlewarePC = reflect.ValueOf(declareMiddleware(nil, declaration{name: "probe"})).Pointer()
+	declaredHandlerPC    = reflect.ValueOf(declareHandler(nil, declaration{})).Pointer()
+)
+
+func lookupMiddleware(mw func(http.Handler) http.Handler) *declaration {
+	if reflect.ValueOf(mw).Pointer() != declaredMiddlewarePC {
+		return nil
+	}
+	p := &probe{}
+	mw(p)
+	return p.d
+}
+
+func lookupHandler(h http.Handler) *declaration {
+	fn, ok := h.(http.HandlerFunc)
+	if !ok || reflect.ValueOf(fn).Pointer() != declaredHandlerPC {
+		return nil
+	}
+	p := &probe{}
+	fn(nil, (&http.Request{}).WithContext(context.WithValue(context.Background(), probeKey{}, p)))
+	return p.d
+}
+
+// Declare records the types mw maps into the request injector, so that Verify can take
+// them into account. It is only needed for middlewares not created by this package.
+// Interface types are declared using a pointer to the interface, eg. (*kubernetes.Interface)(nil).
+func Declare(mw func(http.Handler) http.Handler, provides ...interface{}) func(http.Handler) http.Handler {
+	return declareMiddleware(mw, declaration{provides: typesOf(provides)})
+}
+
+func typesOf(vals []interface{}) []reflect.Type {
+	types := make([]reflect.Type, 0, len(vals))
+	for _, v := range vals {
+		types = append(types, typeOf(v))
+	}
+	return types
+}
+
+// typeOf returns the type of v or the interface type if v is a pointer to an interface.
+func typeOf(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
+
+func funcName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return reflect.TypeOf(fn).String()
+}
+
+// satisfied mirrors the lookup done by inject.Injector.GetVal.
+func satisfied(t reflect.Type, provided []reflect.Type) bool {
+	for _, p := range provided {
+		if p == t || (t.Kind() == reflect.Interface && p.Implements(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteError describes the unsatisfied dependencies of a single route.
+type RouteError struct {
+	Method  string
+	Route   string
+	Reasons []string
+}
+
+func (e RouteError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Route, strings.Join(e.Reasons, "; "))
+}
+
+// VerifyError is returned by Verify if any route has unsatisfied dependencies.
+type VerifyError struct {
+	Routes []RouteError
+}
+
+func (e *VerifyError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "binding: %d route(s) have unsatisfied dependencies", len(e.Routes))
+	for _, r := range e.Routes {
+		sb.WriteString("\n\t")
+		sb.WriteString(r.Error())
+	}
+	return sb.String()
+}
+
+// Verify walks the routes of r and checks that every parameter of the handlers created
+// by Handler is provided by a middleware in front of it. Middlewares that are not created
+// by this package are ignored, unless their provided types are registered using Declare.
+//
+// Routes are not checked when they are registered: a route with missing dependencies only
+// fails when it is requested. Call Verify after all routes are registered and before the
+// server starts, and exit if it fails.
+func Verify(r chi.Routes) error {
+	var errs []RouteError
+	walk(r, func(method string, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) {
+		if reasons := verifyRoute(handler, middlewares); len(reasons) > 0 {
+			errs = append(errs, RouteError{Method: method, Route: route, Reasons: reasons})
+		}
+	}, "")
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			if errs[i].Route != errs[j].Route {
+				return errs[i].Route < errs[j].Route
+			}
+			return errs[i].Method < errs[j].Method
+		})
+		return &VerifyError{Routes: errs}
+	}
+	return nil
+}
+
+// walk is chi.Walk, except that it keeps the middlewares of inline routers (r.With, r.Group)
+// that sub routers (r.Route, r.Mount) are mounted on.
+func walk(r chi.Routes, walkFn func(method string, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler), parentRoute string, parentMw ...func(http.Handler) http.Handler) {
+	for _, route := range r.Routes() {
+		mws := make([]func(http.Handler) http.Handler, len(parentMw))
+		copy(mws, parentMw)
+		mws = append(mws, r.Middlewares()...)
+
+		if route.SubRoutes != nil {
+			if chain, ok := route.Handlers["*"].(*chi.ChainHandler); ok {
+				mws = append(mws, chain.Middlewares...)
+			}
+			walk(route.SubRoutes, walkFn, parentRoute+route.Pattern, mws...)
+			continue
+		}
+
+		for method, handler := range route.Handlers {
+			if method == "*" {
+				// Ignore a "catchAll" method, since we pass down all the specific methods for each route.
+				continue
+			}
+
+			fullRoute := parentRoute + route.Pattern
+			fullRoute = strings.Replace(fullRoute, "/*/", "/", -1)
+
+			if chain, ok := handler.(*chi.ChainHandler); ok {
+				walkFn(method, fullRoute, chain.Endpoint, append(mws, chain.Middlewares...))
+			} else {
+				walkFn(method, fullRoute, handler, mws)
+			}
+		}
+	}
+}
+
+func verifyRoute(handler http.Handler, middlewares []func(http.Handler) http.Handler) []string {
+	var reasons []string
+	var provided, scoped []reflect.Type
+	hasInjector := false
+
+	check := func(name string, requires []reflect.Type) {
+		if !hasInjector {
+			reasons = append(reasons, fmt.Sprintf("%s is used before the Injector middleware", name))
+			return
+		}
+		for _, t := range requires {
+			if !satisfied(t, provided) && !satisfied(t, scoped) {
+				reasons = append(reasons, fmt.Sprintf("%s requires %s, which is not provided", name, t))
+			}
+		}
+	}
+
+	for _, mw := range middlewares {
+		d := lookupMiddleware(mw)
+		if d == nil {
+			continue
+		}
+		switch {
+		case d.injector:
+			hasInjector = true
+		case d.scope != nil:
+			// a scope replaces the scope set by previous middlewares
+			scoped = d.scope.Types()
+			check(d.name, d.scope.requires())
+		case len(d.requires) > 0 || !hasInjector:
+			check(d.name, d.requires)
+		}
+		provided = append(provided, d.provides...)
+	}
+	if d := lookupHandler(handler); d != nil {
+		check(d.name, d.requires)
+	}
+	return reasons
+}
@@ -126,6 +126,14 @@ func GetLogEntry(r *http.Request) logr.Logger {
 	return entry.log
 }
 
+// LookupLogEntry returns the request-scoped logger, if the request is logged by NewLogr.
+func LookupLogEntry(r *http.Request) (logr.Logger, bool) {
+	if entry, ok := r.Context().Value(middleware.LogEntryCtxKey).(*LogrEntry); ok {
+		return entry.log, true
+	}
+	return nil, false
+}
+
 func LogEntrySetField(r *http.Request, key string, value interface{}) {
 	if entry, ok := r.Context().Value(middleware.LogEntryCtxKey).(*LogrEntry); ok {
 		entry.log = entry.log.WithValues(key, value)
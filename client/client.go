@@ -0,0 +1,228 @@
+// Package client calls the APIs of servers built with binding and responsewriters. It is the
+// client side counterpart of responsewriters.ErrorNegotiated: error responses, be they
+// metav1.Status in JSON or YAML or problem details, are returned as *apierrors.StatusError, so
+// that apierrors.IsNotFound and friends work on the client as they do on the server:
+//
+//	c, err := client.New("http://users.svc:3333")
+//	var u User
+//	err = c.Get(ctx, "/users/"+url.PathEscape(name), &u)
+//	if apierrors.IsNotFound(err) {
+//		...
+//	}
+//
+// The request ID of ctx set by chim.RequestID is propagated to the server, and requests with
+// idempotent methods, eg. GET, PUT and DELETE, that are rejected with 429 Too Many Requests or
+// 503 Service Unavailable are retried after the delay asked for by the server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/problem"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Client sends requests to the API of a server and decodes its responses.
+type Client struct {
+	// HTTPClient sends the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a request rejected with 429 Too Many Requests or
+	// 503 Service Unavailable is retried. Requests are not retried if it is 0. Only requests
+	// with idempotent methods are retried, as the server may have acted on the others, eg. a
+	// POST that timed out behind a proxy.
+	MaxRetries int
+	// Backoff is the delay before the first retry of a response without Retry-After. It is
+	// doubled for every further retry.
+	Backoff time.Duration
+	// MaxRetryWait caps the delay before a retry, including the ones asked for by the server.
+	MaxRetryWait time.Duration
+
+	base *url.URL
+}
+
+// New returns a client of the API at baseURL, which retries rejected requests up to 3 times.
+func New(baseURL string) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("client: base URL %q must be absolute", baseURL)
+	}
+	// paths are resolved relative to the base URL, which must be a directory to keep its last segment
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+		if base.RawPath != "" {
+			base.RawPath += "/"
+		}
+	}
+	base.RawQuery, base.Fragment = "", ""
+	return &Client{
+		MaxRetries:   3,
+		Backoff:      500 * time.Millisecond,
+		MaxRetryWait: 30 * time.Second,
+		base:         base,
+	}, nil
+}
+
+// Get gets path and decodes the response into the object out points to.
+func (c *Client) Get(ctx context.Context, path string, out interface{}) error {
+	return c.Do(ctx, http.MethodGet, path, nil, out)
+}
+
+// Post posts in as JSON to path and decodes the response into the object out points to.
+func (c *Client) Post(ctx context.Context, path string, in, out interface{}) error {
+	return c.Do(ctx, http.MethodPost, path, in, out)
+}
+
+// Put puts in as JSON to path and decodes the response into the object out points to.
+func (c *Client) Put(ctx context.Context, path string, in, out interface{}) error {
+	return c.Do(ctx, http.MethodPut, path, in, out)
+}
+
+// Delete deletes path.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	return c.Do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// Do sends a request with method to path, relative to the base URL and with an optional query, with in encoded as JSON
+// as its body unless it is nil. Escaped characters of path are kept, eg. names/a%2Fb. Successful
+// responses are decoded into the object out points to unless it is nil, according to their
+// Content-Type, JSON or YAML. Error responses are returned as *apierrors.StatusError, see StatusErrorOf.
+func (c *Client) Do(ctx context.Context, method, path string, in, out interface{}) error {
+	// "./" keeps a colon in the first segment from being parsed as a scheme
+	rel, err := url.Parse("./" + strings.TrimLeft(path, "/"))
+	if err != nil {
+		return err
+	}
+	u := c.base.ResolveReference(rel)
+
+	var body []byte
+	if in != nil {
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	for retry := 0; ; retry++ {
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json, "+problem.MediaType+";q=0.9, application/yaml;q=0.8")
+		if reqID := chim.GetReqID(ctx); reqID != "" {
+			req.Header.Set(middleware.RequestIDHeader, reqID)
+		}
+
+		resp, err := c.httpClient().Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			err := StatusErrorOf(resp)
+			if delay, ok := c.retryDelay(method, err, retry); ok {
+				if err := sleep(ctx, delay); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		return decodeBody(resp, out)
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// retryDelay returns the delay before retrying a request with method that failed with err for
+// the retry-th time.
+func (c *Client) retryDelay(method string, err *apierrors.StatusError, retry int) (time.Duration, bool) {
+	// the code is checked as well, statuses may lack a reason
+	code := err.ErrStatus.Code
+	if retry >= c.MaxRetries || !isIdempotent(method) || code != http.StatusTooManyRequests && code != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	delay := c.Backoff << uint(retry)
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+	if c.MaxRetryWait > 0 && delay > c.MaxRetryWait {
+		delay = c.MaxRetryWait
+	}
+	return delay, true
+}
+
+// isIdempotent returns true for the methods whose requests may be sent again, see RFC 7231 section 4.2.2.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleep waits for delay, unless ctx is done first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// decodeBody decodes the successful response resp into the object out points to.
+func decodeBody(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if isYAML(mediaType) {
+		return yaml.Unmarshal(data, out)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("client: decode %s response: %w", mediaType, err)
+	}
+	return nil
+}
+
+func isYAML(mediaType string) bool {
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+// newTestClient returns a client of an httptest server serving handler, with retries that
+// don't slow the tests down.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := New(srv.URL + "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Backoff = time.Millisecond
+	c.MaxRetryWait = 10 * time.Millisecond
+	return c
+}
+
+func TestDoURL(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"users", "/api/v1/users"},
+		{"/users/badger", "/api/v1/users/badger"},
+		{"/users/a%2Fb", "/api/v1/users/a%2Fb"},
+		{"/users?limit=10&continue=a%26b", "/api/v1/users?limit=10&continue=a%26b"},
+		{"users:search", "/api/v1/users:search"},
+		{"//example.com/users", "/api/v1/example.com/users"},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			var got string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				got = r.RequestURI
+			})
+			if err := client.Get(context.Background(), c.path, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDoHeaders(t *testing.T) {
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	})
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "01FX")
+	if err := c.Post(ctx, "users", user{Name: "badger"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(middleware.RequestIDHeader); got != "01FX" {
+		t.Errorf("got request ID %q, want 01FX", got)
+	}
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", got)
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"JSON", "application/json", `{"name": "badger"}`},
+		{"YAML", "application/yaml", "name: badger\n"},
+		{"YAML suffix", "application/vnd.users+yaml", "name: badger\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", c.contentType)
+				_, _ = w.Write([]byte(c.body))
+			})
+			var u user
+			if err := client.Get(context.Background(), "users/badger", &u); err != nil {
+				t.Fatal(err)
+			}
+			if u.Name != "badger" {
+				t.Errorf("got %+v, want badger", u)
+			}
+		})
+	}
+}
+
+func TestStatusErrorOf(t *testing.T) {
+	cases := []struct {
+		name        string
+		code        int
+		contentType string
+		retryAfter  string
+		body        string
+		want        metav1.Status
+	}{
+		{
+			name:        "JSON",
+			code:        http.StatusNotFound,
+			contentType: "application/json",
+			body:        `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"users \"badger\" not found","reason":"NotFound","details":{"name":"badger","kind":"users"},"code":404}`,
+			want: metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure, Code: 404, Reason: metav1.StatusReasonNotFound,
+				Message: `users "badger" not found`,
+				Details: &metav1.StatusDetails{Name: "badger", Kind: "users"},
+			},
+		},
+		{
+			name:        "YAML",
+			code:        http.StatusConflict,
+			contentType: "application/yaml",
+			body:        "kind: Status\napiVersion: v1\nmessage: users \"badger\" already exists\nreason: AlreadyExists\n",
+			want: metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure, Code: 409, Reason: metav1.StatusReasonAlreadyExists,
+				Message: `users "badger" already exists`,
+			},
+		},
+		{
+			name:        "problem details",
+			code:        http.StatusUnprocessableEntity,
+			contentType: "application/problem+json",
+			body:        `{"type":"urn:k8s:status-reason:Invalid","title":"Unprocessable Entity","status":422,"detail":"User is invalid","invalid-params":[{"name":"name","reason":"name is a required field","type":"FieldValueRequired"}]}`,
+		},
+		{
+			name:        "Retry-After",
+			code:        http.StatusTooManyRequests,
+			contentType: "application/json",
+			retryAfter:  "7",
+			body:        `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"slow down","reason":"TooManyRequests","code":429}`,
+			want: metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure, Code: 429, Reason: metav1.StatusReasonTooManyRequests,
+				Message: "slow down",
+				Details: &metav1.StatusDetails{RetryAfterSeconds: 7},
+			},
+		},
+		{
+			name:        "not a status",
+			code:        http.StatusBadGateway,
+			contentType: "text/html",
+			body:        "<html>bad gateway</html>",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", c.contentType)
+			if c.retryAfter != "" {
+				rec.Header().Set("Retry-After", c.retryAfter)
+			}
+			rec.WriteHeader(c.code)
+			_, _ = rec.WriteString(c.body)
+
+			got := StatusErrorOf(rec.Result()).ErrStatus
+			switch c.name {
+			case "problem details":
+				if got.Code != 422 || got.Reason != metav1.StatusReasonInvalid || got.Message != "User is invalid" ||
+					got.Details == nil || len(got.Details.Causes) != 1 || got.Details.Causes[0].Field != "name" {
+					t.Errorf("got %+v", got)
+				}
+			case "not a status":
+				if got.Code != 502 || got.Reason != metav1.StatusReasonInternalError || got.Details == nil ||
+					len(got.Details.Causes) != 1 || got.Details.Causes[0].Message != c.body {
+					t.Errorf("got %+v", got)
+				}
+			default:
+				if !reflect.DeepEqual(got, c.want) {
+					t.Errorf("got %+v, want %+v", got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		method   string
+		failures int32
+		code     int
+		calls    int32
+		errCode  int32
+	}{
+		{"GET recovers", http.MethodGet, 2, http.StatusServiceUnavailable, 3, 0},
+		{"PUT recovers", http.MethodPut, 1, http.StatusTooManyRequests, 2, 0},
+		{"GET gives up", http.MethodGet, 10, http.StatusServiceUnavailable, 4, http.StatusServiceUnavailable},
+		{"POST is not retried", http.MethodPost, 1, http.StatusServiceUnavailable, 1, http.StatusServiceUnavailable},
+		{"other errors are not retried", http.MethodGet, 1, http.StatusInternalServerError, 1, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var calls int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				// the status has no reason, the code alone decides whether to retry
+				if atomic.AddInt32(&calls, 1) <= c.failures {
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(c.code)
+					_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"try again"}`))
+				}
+			})
+			err := client.Do(context.Background(), c.method, "users", nil, nil)
+			if calls != c.calls {
+				t.Errorf("got %d calls, want %d", calls, c.calls)
+			}
+			var se *apierrors.StatusError
+			switch {
+			case c.errCode == 0 && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case c.errCode != 0 && (!errors.As(err, &se) || se.ErrStatus.Code != c.errCode):
+				t.Errorf("got %v, want a %d status error", err, c.errCode)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	c.MaxRetryWait = time.Minute
+	if err := c.Get(ctx, "users", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &Client{MaxRetries: 3, Backoff: 100 * time.Millisecond, MaxRetryWait: 5 * time.Second}
+	unavailable := apierrors.NewServiceUnavailable("down")
+	cases := []struct {
+		name   string
+		method string
+		err    *apierrors.StatusError
+		retry  int
+		delay  time.Duration
+		ok     bool
+	}{
+		{"first retry", http.MethodGet, unavailable, 0, 100 * time.Millisecond, true},
+		{"backoff doubles", http.MethodGet, unavailable, 2, 400 * time.Millisecond, true},
+		{"max retries", http.MethodGet, unavailable, 3, 0, false},
+		{"Retry-After", http.MethodDelete, apierrors.NewTooManyRequests("slow down", 2), 0, 2 * time.Second, true},
+		{"Retry-After capped", http.MethodGet, apierrors.NewTooManyRequests("slow down", 60), 0, 5 * time.Second, true},
+		{"POST", http.MethodPost, unavailable, 0, 0, false},
+		{"PATCH", http.MethodPatch, unavailable, 0, 0, false},
+		{"not found", http.MethodGet, apierrors.NewNotFound(schema.GroupResource{Resource: "users"}, "badger"), 0, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			delay, ok := client.retryDelay(c.method, c.err, c.retry)
+			if delay != c.delay || ok != c.ok {
+				t.Errorf("got %v, %v, want %v, %v", delay, ok, c.delay, c.ok)
+			}
+		})
+	}
+}
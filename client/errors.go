@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tamalsaha/learn-chi/problem"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// maxErrorBody limits the part of an error response that is read.
+const maxErrorBody = 1 << 20
+
+// StatusErrorOf returns the error of the failed response resp and closes its body. Bodies
+// written by responsewriters.ErrorNegotiated, ie. metav1.Status in JSON or YAML and problem
+// details, are decoded into the status of the error. Other bodies result in the generic error
+// of the status code, with the body as the cause, like client-go does. The Retry-After header
+// is kept as the RetryAfterSeconds of the details.
+func StatusErrorOf(resp *http.Response) *apierrors.StatusError {
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+	var status *metav1.Status
+	if err == nil {
+		status = decodeStatus(resp.Header.Get("Content-Type"), data)
+	}
+	if status == nil {
+		method := ""
+		if resp.Request != nil {
+			method = resp.Request.Method
+		}
+		return apierrors.NewGenericServerResponse(resp.StatusCode, method, schema.GroupResource{}, "", strings.TrimSpace(string(data)), retryAfter, true)
+	}
+
+	if status.Code == 0 {
+		status.Code = int32(resp.StatusCode)
+	}
+	if status.Status == "" {
+		status.Status = metav1.StatusFailure
+	}
+	if retryAfter > 0 && (status.Details == nil || status.Details.RetryAfterSeconds == 0) {
+		if status.Details == nil {
+			status.Details = &metav1.StatusDetails{}
+		}
+		status.Details.RetryAfterSeconds = int32(retryAfter)
+	}
+	return &apierrors.StatusError{ErrStatus: *status}
+}
+
+// decodeStatus decodes the error body data of contentType, or returns nil if it is not a status.
+func decodeStatus(contentType string, data []byte) *metav1.Status {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == problem.MediaType:
+		var p problem.Problem
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil
+		}
+		return problem.ToStatus(&p)
+	case isYAML(mediaType):
+		var status metav1.Status
+		if err := yaml.Unmarshal(data, &status); err != nil || status.Kind != "Status" {
+			return nil
+		}
+		return &status
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		var status metav1.Status
+		if err := json.Unmarshal(data, &status); err != nil || status.Kind != "Status" {
+			return nil
+		}
+		return &status
+	}
+	return nil
+}
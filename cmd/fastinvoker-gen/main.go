@@ -0,0 +1,359 @@
+// Command fastinvoker-gen generates inject.FastInvoker adapters for the functions passed to
+// binding.Handler in a package, so that they are called without reflection.
+//
+// Usage:
+//
+//	//go:generate go run github.com/tamalsaha/learn-chi/cmd/fastinvoker-gen
+//
+// The package is scanned syntactically. Handlers must be functions declared in the package or
+// function literals passed directly to binding.Handler. Method values, function variables and
+// variadic functions are skipped and keep using reflection.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const bindingPkg = "github.com/tamalsaha/learn-chi/binding"
+
+var output = flag.String("o", "zz_generated.fastinvoker.go", "name of the generated file")
+
+// signature is a handler signature rendered as Go source, eg. func(*http.Request) string.
+type signature struct {
+	params  []string
+	results []string
+	imports map[string]string // local name -> import path
+}
+
+func (s signature) String() string {
+	out := strings.Join(s.results, ", ")
+	if len(s.results) > 1 {
+		out = "(" + out + ")"
+	}
+	return strings.TrimSpace(fmt.Sprintf("func(%s) %s", strings.Join(s.params, ", "), out))
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("fastinvoker-gen: ")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	pkgName, sigs, err := scan(dir)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	src, err := generate(pkgName, sigs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, *output), src, 0644); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// scan returns the signatures of the functions passed to binding.Handler in the package in dir.
+func scan(dir string) (string, []signature, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != *output
+	}, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected 1 package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	// handlers are declared in any file of the package, their types are resolved with the
+	// imports of the file declaring them
+	funcs := map[string]*ast.FuncDecl{}
+	declImports := map[*ast.FuncDecl]map[string]string{}
+	for _, file := range pkg.Files {
+		imports := fileImports(file)
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil {
+				funcs[fd.Name.Name] = fd
+				declImports[fd] = imports
+			}
+		}
+	}
+
+	names := newImportNames(pkg.Name)
+	sigs := map[string]signature{}
+	cache := map[*ast.FuncType]signature{}
+	for _, file := range pkg.Files {
+		imports := fileImports(file)
+		bindingName := ""
+		for name, path := range imports {
+			if path == bindingPkg {
+				bindingName = name
+			}
+		}
+		if bindingName == "" {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 || !isSelector(call.Fun, bindingName, "Handler") {
+				return true
+			}
+
+			var ft *ast.FuncType
+			typeImports := imports
+			switch arg := call.Args[0].(type) {
+			case *ast.Ident:
+				if fd, ok := funcs[arg.Name]; ok {
+					ft, typeImports = fd.Type, declImports[fd]
+				}
+			case *ast.FuncLit:
+				ft = arg.Type
+			}
+			if ft == nil {
+				return true
+			}
+			if isVariadic(ft) {
+				log.Printf("%s: skipping variadic handler, it keeps using reflection", fset.Position(call.Args[0].Pos()))
+				return true
+			}
+
+			sig, ok := cache[ft]
+			if !ok {
+				sig = newSignature(fset, ft, typeImports, names)
+				cache[ft] = sig
+			}
+			sigs[sig.String()] = sig
+			return true
+		})
+	}
+
+	keys := make([]string, 0, len(sigs))
+	for k := range sigs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]signature, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, sigs[k])
+	}
+	return pkg.Name, out, nil
+}
+
+// isVariadic returns true if the last parameter of ft is variadic, eg. func(opts ...Option).
+func isVariadic(ft *ast.FuncType) bool {
+	if ft.Params == nil || len(ft.Params.List) == 0 {
+		return false
+	}
+	_, ok := ft.Params.List[len(ft.Params.List)-1].Type.(*ast.Ellipsis)
+	return ok
+}
+
+func fileImports(file *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else if strings.HasPrefix(name, "v") && strings.Contains(path, "/") {
+			// major version suffix, eg. github.com/go-chi/chi/v5
+			if _, err := strconv.Atoi(name[1:]); err == nil {
+				trimmed := path[:strings.LastIndex(path, "/")]
+				name = trimmed[strings.LastIndex(trimmed, "/")+1:]
+			}
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg
+}
+
+// importNames assigns the names of the imports of the generated file, so that packages imported
+// under different names by the files of the package, or different packages imported under the
+// same name, get one name each.
+type importNames struct {
+	byPath map[string]string
+	byName map[string]string
+}
+
+func newImportNames(pkgName string) *importNames {
+	n := &importNames{byPath: map[string]string{}, byName: map[string]string{}}
+	n.name("reflect", "reflect")
+	n.name("inject", "go.wandrs.dev/inject")
+	if pkgName != "binding" {
+		n.name("binding", bindingPkg)
+	}
+	return n
+}
+
+// name returns the name of the import of path, preferring local, its name in a source file.
+func (n *importNames) name(local, path string) string {
+	if name, ok := n.byPath[path]; ok {
+		return name
+	}
+	name := local
+	for i := 2; n.byName[name] != ""; i++ {
+		name = fmt.Sprintf("%s%d", local, i)
+	}
+	n.byPath[path], n.byName[name] = name, path
+	return name
+}
+
+// newSignature returns the signature of ft, whose package selectors are resolved with imports,
+// the imports of the file declaring ft, and renamed to the names of the generated file. It
+// rewrites the selectors of ft, so it must be called once per ft.
+func newSignature(fset *token.FileSet, ft *ast.FuncType, imports map[string]string, names *importNames) signature {
+	sig := signature{imports: map[string]string{}}
+	render := func(fields *ast.FieldList) []string {
+		var out []string
+		if fields == nil {
+			return out
+		}
+		for _, field := range fields.List {
+			ast.Inspect(field.Type, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					if id, ok := sel.X.(*ast.Ident); ok {
+						if path, ok := imports[id.Name]; ok {
+							id.Name = names.name(id.Name, path)
+							sig.imports[id.Name] = path
+						}
+					}
+				}
+				return true
+			})
+			var buf bytes.Buffer
+			_ = printer.Fprint(&buf, fset, field.Type)
+			n := len(field.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				out = append(out, buf.String())
+			}
+		}
+		return out
+	}
+	sig.params = render(ft.Params)
+	sig.results = render(ft.Results)
+	return sig
+}
+
+func generate(pkgName string, sigs []signature) ([]byte, error) {
+	if len(sigs) == 0 {
+		return format.Source([]byte(fmt.Sprintf("// Code generated by fastinvoker-gen. DO NOT EDIT.\n\npackage %s\n", pkgName)))
+	}
+
+	imports := map[string]string{
+		"reflect": "reflect",
+		"inject":  "go.wandrs.dev/inject",
+	}
+	if pkgName != "binding" {
+		imports["binding"] = bindingPkg
+	}
+	for _, sig := range sigs {
+		for name, path := range sig.imports {
+			imports[name] = path
+		}
+	}
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		names = append(names, name)
+	}
+	// standard library imports first, like goimports
+	isStd := func(path string) bool {
+		return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := imports[names[i]], imports[names[j]]
+		if isStd(pi) != isStd(pj) {
+			return isStd(pi)
+		}
+		return pi < pj
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by fastinvoker-gen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkgName)
+	for i, name := range names {
+		path := imports[name]
+		if i > 0 && isStd(imports[names[i-1]]) && !isStd(path) {
+			buf.WriteString("\n")
+		}
+		if path == name || strings.HasSuffix(path, "/"+name) {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(&buf, "\t%s %q\n", name, path)
+		}
+	}
+	buf.WriteString(")\n")
+
+	qualifier := "binding."
+	if pkgName == "binding" {
+		qualifier = ""
+	}
+	for i, sig := range sigs {
+		typeName := fmt.Sprintf("fastInvoker%d", i)
+		fmt.Fprintf(&buf, "\n// %s calls handlers of type %s without reflection.\n", typeName, sig)
+		fmt.Fprintf(&buf, "type %s %s\n\n", typeName, sig)
+		fmt.Fprintf(&buf, "func (f %s) Invoke(args []interface{}) ([]reflect.Value, error) {\n", typeName)
+		args := make([]string, len(sig.params))
+		for j, p := range sig.params {
+			fmt.Fprintf(&buf, "\ta%d, _ := args[%d].(%s)\n", j, j, p)
+			args[j] = fmt.Sprintf("a%d", j)
+		}
+		call := fmt.Sprintf("f(%s)", strings.Join(args, ", "))
+		if len(sig.results) == 0 {
+			fmt.Fprintf(&buf, "\t%s\n\treturn nil, nil\n}\n", call)
+			continue
+		}
+		results := make([]string, len(sig.results))
+		values := make([]string, len(sig.results))
+		for j := range sig.results {
+			results[j] = fmt.Sprintf("r%d", j)
+			// reflect.ValueOf(&r).Elem() keeps the declared type, eg. a nil error
+			values[j] = fmt.Sprintf("reflect.ValueOf(&r%d).Elem()", j)
+		}
+		fmt.Fprintf(&buf, "\t%s := %s\n", strings.Join(results, ", "), call)
+		fmt.Fprintf(&buf, "\treturn []reflect.Value{%s}, nil\n}\n", strings.Join(values, ", "))
+	}
+
+	buf.WriteString("\nfunc init() {\n")
+	for i, sig := range sigs {
+		fmt.Fprintf(&buf, "\t%sRegisterFastInvoker(reflect.TypeOf((%s)(nil)), func(fn interface{}) inject.FastInvoker {\n", qualifier, sig)
+		fmt.Fprintf(&buf, "\t\treturn fastInvoker%d(fn.(%s))\n\t})\n", i, sig)
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
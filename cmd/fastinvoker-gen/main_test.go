@@ -0,0 +1,99 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestScanImportsOfDeclaringFile checks that the types of handlers declared in another file than
+// the one calling binding.Handler are resolved with the imports of the declaring file, and that
+// packages imported under the same name by different files get distinct names.
+func TestScanImportsOfDeclaringFile(t *testing.T) {
+	files := map[string]string{
+		"routes.go": `package app
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/tamalsaha/learn-chi/binding"
+	v1 "k8s.io/api/core/v1"
+)
+
+func routes(r chi.Router) {
+	r.Get("/meta", binding.Handler(getMeta))
+	r.Get("/pod", binding.Handler(getPod))
+	r.Get("/node", binding.Handler(func(n v1.Node) string { return n.Name }))
+}
+`,
+		"meta.go": `package app
+
+import (
+	nethttp "net/http"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getMeta(r *nethttp.Request, m v1.ObjectMeta) (*v1.Status, error) { return nil, nil }
+`,
+		"pod.go": `package app
+
+import v1 "k8s.io/api/core/v1"
+
+func getPod(p v1.Pod) string { return p.Name }
+`,
+	}
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkgName, sigs, err := scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, sig := range sigs {
+		got = append(got, sig.String())
+	}
+	want := []string{
+		"func(*nethttp.Request, v1.ObjectMeta) (*v1.Status, error)",
+		"func(v12.Node) string",
+		"func(v12.Pod) string",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got signatures\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	src, err := generate(pkgName, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+	imports := map[string]string{}
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[path] = name
+	}
+	for path, name := range map[string]string{
+		"net/http":                             "nethttp",
+		"k8s.io/api/core/v1":                   "v12",
+		"k8s.io/apimachinery/pkg/apis/meta/v1": "",
+	} {
+		if got, ok := imports[path]; !ok || got != name {
+			t.Errorf("got import %q named %q, want %q\n%s", path, got, name, src)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/unrolled/render"
+)
+
+//go:generate go run ../cmd/fastinvoker-gen
+
+type Person struct {
+	Name string `json:"name"`
+}
+
+func hello(r *http.Request) string {
+	return "Hello " + r.URL.Query().Get("name")
+}
+
+func person(ctx context.Context, r *http.Request) (Person, error) {
+	return Person{Name: r.URL.Query().Get("name")}, ctx.Err()
+}
+
+func status(w http.ResponseWriter) (int, Person, error) {
+	return http.StatusCreated, Person{Name: "John"}, nil
+}
+
+func main() {
+	r := chi.NewRouter()
+	r.Use(binding.Injector(render.New()))
+	r.Get("/hello", binding.Handler(hello))
+	r.Get("/person", binding.Handler(person))
+	r.Post("/person", binding.Handler(status))
+
+	if err := binding.Verify(r); err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Println("running server on :3333")
+	http.ListenAndServe(":3333", r)
+}
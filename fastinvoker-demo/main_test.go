@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/unrolled/render"
+	"go.wandrs.dev/inject"
+)
+
+// handlers are the handlers of the benchmarks with their generated adapters.
+var handlers = []struct {
+	name string
+	fn   interface{}
+	fast inject.FastInvoker
+}{
+	{"hello", hello, fastInvoker0(hello)},
+	{"person", person, fastInvoker1(person)},
+	{"status", status, fastInvoker2(status)},
+}
+
+func newInjector() inject.Injector {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/?name=tamal", nil)
+
+	injector := inject.New()
+	injector.Map(req.Context())
+	injector.Map(req)
+	injector.MapTo(w, (*http.ResponseWriter)(nil))
+	return injector
+}
+
+func BenchmarkReflectInvoke(b *testing.B) {
+	for _, h := range handlers {
+		b.Run(h.name, func(b *testing.B) {
+			injector := newInjector()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := injector.Invoke(h.fn); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFastInvoke(b *testing.B) {
+	for _, h := range handlers {
+		b.Run(h.name, func(b *testing.B) {
+			injector := newInjector()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := injector.Invoke(h.fast); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHandler measures a full request served by binding.Handler, which picks up the generated adapter.
+func BenchmarkHandler(b *testing.B) {
+	for _, h := range handlers {
+		b.Run(h.name, func(b *testing.B) {
+			handler := binding.Injector(render.New())(binding.Handler(h.fn))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/?name=tamal", nil)
+				handler.ServeHTTP(w, req)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by fastinvoker-gen. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/tamalsaha/learn-chi/binding"
+	"go.wandrs.dev/inject"
+)
+
+// fastInvoker0 calls handlers of type func(*http.Request) string without reflection.
+type fastInvoker0 func(*http.Request) string
+
+func (f fastInvoker0) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(*http.Request)
+	r0 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem()}, nil
+}
+
+// fastInvoker1 calls handlers of type func(context.Context, *http.Request) (Person, error) without reflection.
+type fastInvoker1 func(context.Context, *http.Request) (Person, error)
+
+func (f fastInvoker1) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(context.Context)
+	a1, _ := args[1].(*http.Request)
+	r0, r1 := f(a0, a1)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem(), reflect.ValueOf(&r1).Elem()}, nil
+}
+
+// fastInvoker2 calls handlers of type func(http.ResponseWriter) (int, Person, error) without reflection.
+type fastInvoker2 func(http.ResponseWriter) (int, Person, error)
+
+func (f fastInvoker2) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(http.ResponseWriter)
+	r0, r1, r2 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem(), reflect.ValueOf(&r1).Elem(), reflect.ValueOf(&r2).Elem()}, nil
+}
+
+func init() {
+	binding.RegisterFastInvoker(reflect.TypeOf((func(*http.Request) string)(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker0(fn.(func(*http.Request) string))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(context.Context, *http.Request) (Person, error))(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker1(fn.(func(context.Context, *http.Request) (Person, error)))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(http.ResponseWriter) (int, Person, error))(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker2(fn.(func(http.ResponseWriter) (int, Person, error)))
+	})
+}
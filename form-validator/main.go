@@ -3,13 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/go-playground/form/v4"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"net/http"
-	"reflect"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/tamalsaha/learn-chi/binding/defaults"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
 )
 
 // User contains user information
@@ -20,6 +17,7 @@ type User struct {
 	Email          string     `validate:"required,email"`
 	FavouriteColor string     `validate:"iscolor"`                // alias for 'hexcolor|rgb|rgba|hsl|hsla'
 	Addresses      []*Address `validate:"required,dive,required"` // a person can have a home and cottage...
+	Language       string     `default:"en" validate:"oneof=en de"`
 }
 
 // Address houses a users address information
@@ -36,6 +34,14 @@ var validate *validator.Validate
 func main() {
 
 	validate = validator.New()
+	validate.RegisterTagNameFunc(bindingerrors.TagNameFunc("json"))
+
+	// addresses are on earth, unless stated otherwise
+	defaults.Register(func(a *Address) {
+		if a.Planet == "" {
+			a.Planet = "Earth"
+		}
+	})
 
 	validateStruct()
 	//validateVariable()
@@ -45,7 +51,6 @@ func validateStruct() {
 
 	address := &Address{
 		Street: "Eavesdown Docks",
-		Planet: "Persphone",
 		Phone:  "none",
 	}
 
@@ -58,6 +63,11 @@ func validateStruct() {
 		Addresses:      []*Address{address},
 	}
 
+	// sets Language and Planet, which are optional
+	if err := defaults.Apply(user); err != nil {
+		panic(err)
+	}
+
 	// returns nil or ValidationErrors ( []FieldError )
 	err := validate.Struct(user)
 	if err != nil {
@@ -86,6 +96,8 @@ func validateStruct() {
 		}
 
 		// from here you can create your own error messages in whatever language you wish
+		out, _ := json.MarshalIndent(bindingerrors.NewBindingError(err, user).ErrStatus, "", "  ")
+		fmt.Println(string(out))
 		return
 	}
 
@@ -105,88 +117,3 @@ func validateVariable() {
 
 	// email ok, move on
 }
-
-func NewBindingError(err error, obj interface{}) *apierrors.StatusError {
-	if err == nil {
-		return &apierrors.StatusError{metav1.Status{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "Status",
-				APIVersion: "v1",
-			},
-			Status: metav1.StatusSuccess,
-			Code:   http.StatusOK,
-		}}
-	}
-
-	switch t := err.(type) {
-	case *validator.InvalidValidationError:
-		return &apierrors.StatusError{metav1.Status{
-			Status: metav1.StatusFailure,
-			Code:   http.StatusUnprocessableEntity,
-			Reason: metav1.StatusReasonInvalid,
-			//Details: &metav1.StatusDetails{
-			//	Group:  qualifiedKind.Group,
-			//	Kind:   qualifiedKind.Kind,
-			//	Name:   name,
-			//	Causes: causes,
-			//},
-			Message: err.Error(),
-		}}
-	case validator.ValidationErrors:
-		causes := make([]metav1.StatusCause, 0, len(t))
-		for i := range t {
-			err := t[i]
-			st := metav1.CauseTypeFieldValueInvalid
-			if err.Tag() == "required" {
-				st = metav1.CauseTypeFieldValueRequired
-			}
-			causes = append(causes, metav1.StatusCause{
-				Type:    st,
-				Message: err.Error(),
-				Field:   err.Namespace(),
-			})
-		}
-		return &apierrors.StatusError{metav1.Status{
-			Status: metav1.StatusFailure,
-			Code:   http.StatusUnprocessableEntity,
-			Reason: metav1.StatusReasonInvalid,
-			Details: &metav1.StatusDetails{
-				//Group:  qualifiedKind.Group,
-				//Kind:   qualifiedKind.Kind,
-				//Name:   name,
-				Causes: causes,
-			},
-			// Message: fmt.Sprintf("%s %q is invalid: %v", qualifiedKind.String(), name, errs.ToAggregate()),
-			Message: fmt.Sprintf("%s is invalid", reflect.TypeOf(obj)),
-		}}
-	case form.DecodeErrors:
-		ot := reflect.TypeOf(obj)
-		if ot.Kind() == reflect.Interface {
-			ot = ot.Elem()
-		}
-		causes := make([]metav1.StatusCause, 0, len(t))
-		for field, err := range t {
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: err.Error(),
-				Field:   field,
-			})
-		}
-		return &apierrors.StatusError{metav1.Status{
-			Status: metav1.StatusFailure,
-			Code:   http.StatusBadRequest,
-			Reason: metav1.StatusReasonBadRequest,
-			Details: &metav1.StatusDetails{
-				// Group:  qualifiedKind.Group,
-				//Kind:   qualifiedKind.Kind,
-				//Name:   name,
-				Causes: causes,
-			},
-			Message: fmt.Sprintf("failed to decode into %s", reflect.TypeOf(obj)),
-		}}
-	case *form.InvalidDecoderError, *json.InvalidUnmarshalError:
-		return apierrors.NewInternalError(err) // error due to bug in source code
-	default:
-		return apierrors.NewBadRequest(err.Error()) // error due to bad input from request body
-	}
-}
@@ -4,15 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"log"
-	"net/http"
 	"net/url"
 	"os"
 	"reflect"
 
 	"github.com/go-playground/form/v4"
+	bindingerrors "github.com/tamalsaha/learn-chi/binding/errors"
 )
 
 // <form method="POST">
@@ -52,9 +50,6 @@ type User struct {
 var decoder *form.Decoder
 
 func main__() {
-	sr := ToAPIError(nil, nil)
-	fmt.Println(sr)
-
 	decoder = form.NewDecoder()
 	decoder.SetTagName("json")
 	// decoder.SetMode(form.ModeExplicit)
@@ -67,7 +62,12 @@ func main__() {
 	// must pass a pointer
 	err := decoder.Decode(&user, values)
 	if err != nil {
-		log.Panic(err)
+		log.Panic(bindingerrors.NewBindingError(err, &user))
+	}
+
+	// strict mode: the decoder drops keys without a field silently
+	if unknown := bindingerrors.UnknownFormFields(values, &user, "json"); len(unknown) > 0 {
+		log.Panic(bindingerrors.NewBindingError(&bindingerrors.UnknownFieldsError{Fields: unknown}, &user))
 	}
 
 	fmt.Printf("%#v\n", user)
@@ -90,51 +90,6 @@ func parseForm() url.Values {
 	}
 }
 
-func ToAPIError(err error, obj interface{}) *apierrors.StatusError {
-	if err == nil {
-		return &apierrors.StatusError{metav1.Status{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "Status",
-				APIVersion: "v1",
-			},
-			Status: metav1.StatusSuccess,
-			Code:   http.StatusOK,
-		}}
-	}
-
-	switch t := err.(type) {
-	case form.DecodeErrors:
-		ot := reflect.TypeOf(obj)
-		if ot.Kind() == reflect.Ptr {
-			ot = ot.Elem()
-		}
-		causes := make([]metav1.StatusCause, 0, len(t))
-		for field, err := range t {
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: err.Error(),
-				Field:   field,
-			})
-		}
-		return &apierrors.StatusError{metav1.Status{
-			Status: metav1.StatusFailure,
-			Code:   http.StatusBadRequest,
-			Reason: metav1.StatusReasonBadRequest,
-			Details: &metav1.StatusDetails{
-				//Group:  qualifiedKind.Group,
-				//Kind:   qualifiedKind.Kind,
-				//Name:   name,
-				Causes: causes,
-			},
-			Message: fmt.Sprintf("failed to decode into %s.%s", ot.PkgPath(), ot.Name()),
-		}}
-	case *form.InvalidDecoderError:
-		return apierrors.NewInternalError(err)
-	default:
-		return apierrors.NewInternalError(err)
-	}
-}
-
 // As finds the first error in err's chain that matches target, and if so, sets
 // target to that error value and returns true. Otherwise, it returns false.
 //
@@ -6,16 +6,19 @@ require (
 	github.com/go-chi/chi/v5 v5.0.3
 	github.com/go-logr/logr v0.4.0
 	github.com/go-playground/form/v4 v4.1.3
+	github.com/go-playground/locales v0.13.0
+	github.com/go-playground/universal-translator v0.17.0
 	github.com/go-playground/validator/v10 v10.6.1
 	github.com/oklog/ulid/v2 v2.0.2
 	github.com/oschwald/geoip2-golang v1.5.0
 	github.com/unrolled/render v1.4.0
-	go.wandrs.dev/binding v0.0.0-20210620235157-b2562622a8ce
 	go.wandrs.dev/http v0.0.0-20210620094415-abb1017550b9
 	go.wandrs.dev/inject v0.0.0-20210615003440-96c9194068f9
 	gomodules.xyz/ulids v0.1.0
+	k8s.io/api v0.21.2
 	k8s.io/apimachinery v0.21.2
 	k8s.io/apiserver v0.21.2
 	k8s.io/client-go v0.21.2
 	k8s.io/klog/v2 v2.8.0
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -0,0 +1,190 @@
+package i18n
+
+// German are the German messages of the Default catalog. See English for the message keys.
+var German = Messages{
+	// binding errors
+	KeyInvalid:      "{0} ist ungültig",
+	KeyInvalidNamed: "{0} \"{1}\" ist ungültig",
+	KeyDecode:       "Die Anfrage konnte nicht in {0} dekodiert werden",
+	KeyDecodeNamed:  "Die Anfrage konnte nicht in {0} \"{1}\" dekodiert werden",
+	KeyDecodeValue:  "{0} hat einen ungültigen Wert",
+	KeyDecodeType:   "{0} muss vom Typ {1} sein",
+	KeyUnknownField: "unbekanntes Feld \"{0}\"",
+	KeyValidation:   "{0} hat die Validierung {1} nicht bestanden",
+
+	// request bodies
+	KeyBodyEmpty:     "Der Inhalt der Anfrage ist leer",
+	KeyBodyTruncated: "Der Inhalt der Anfrage endet unerwartet",
+	KeyBodySyntax:    "Der Inhalt der Anfrage ist kein gültiges JSON: {0}",
+	KeyBodyPosition:  "{0} in Zeile {1}, Spalte {2}",
+	KeyBodyFormat:    "Der Inhalt der Anfrage ist kein gültiges {0}: {1}",
+	KeyBodyLine:      "{0} in Zeile {1}",
+	KeyItemsInvalid:  "Die {0}-Einträge {1} sind ungültig",
+
+	// errors
+	KeyRedacted: "{0} (Anfrage-ID {1})",
+
+	// status reasons
+	StatusKey("NotFound"):             "Die Ressource wurde nicht gefunden",
+	StatusNamedKey("NotFound"):        "{0} \"{1}\" wurde nicht gefunden",
+	StatusKey("AlreadyExists"):        "Die Ressource existiert bereits",
+	StatusNamedKey("AlreadyExists"):   "{0} \"{1}\" existiert bereits",
+	StatusKey("Gone"):                 "Die Ressource ist nicht mehr verfügbar",
+	StatusKey("MethodNotAllowed"):     "Die Methode ist für die angeforderte Ressource nicht erlaubt",
+	StatusKey("NotAcceptable"):        "Keiner der angeforderten Medientypen wird unterstützt",
+	StatusKey("UnsupportedMediaType"): "Der Medientyp der Anfrage wird nicht unterstützt",
+	StatusKey("TooManyRequests"):      "Zu viele Anfragen, bitte später erneut versuchen",
+	StatusKey("ServiceUnavailable"):   "Der Dienst ist vorübergehend nicht verfügbar",
+	StatusKey("Timeout"):              "Die Anfrage konnte nicht rechtzeitig bearbeitet werden",
+
+	// validator tags
+	"required":                      "{0} ist ein Pflichtfeld",
+	"required_if":                   "{0} ist ein Pflichtfeld",
+	"required_unless":               "{0} ist ein Pflichtfeld",
+	"required_with":                 "{0} ist ein Pflichtfeld",
+	"required_with_all":             "{0} ist ein Pflichtfeld",
+	"required_without":              "{0} ist ein Pflichtfeld",
+	"required_without_all":          "{0} ist ein Pflichtfeld",
+	"excluded_with":                 "{0} darf nicht gesetzt sein",
+	"excluded_with_all":             "{0} darf nicht gesetzt sein",
+	"excluded_without":              "{0} darf nicht gesetzt sein",
+	"excluded_without_all":          "{0} darf nicht gesetzt sein",
+	"isdefault":                     "{0} muss leer sein",
+	"len.string":                    "{0} muss genau {1} Zeichen lang sein",
+	"len.items":                     "{0} muss genau {1} Elemente enthalten",
+	"len.number":                    "{0} muss gleich {1} sein",
+	"min.string":                    "{0} muss mindestens {1} Zeichen lang sein",
+	"min.items":                     "{0} muss mindestens {1} Elemente enthalten",
+	"min.number":                    "{0} muss {1} oder größer sein",
+	"max.string":                    "{0} darf höchstens {1} Zeichen lang sein",
+	"max.items":                     "{0} darf höchstens {1} Elemente enthalten",
+	"max.number":                    "{0} muss {1} oder kleiner sein",
+	"eq":                            "{0} ist nicht gleich {1}",
+	"ne":                            "{0} darf nicht gleich {1} sein",
+	"lt.string":                     "{0} muss weniger als {1} Zeichen lang sein",
+	"lt.items":                      "{0} muss weniger als {1} Elemente enthalten",
+	"lt.number":                     "{0} muss kleiner als {1} sein",
+	"lt.time":                       "{0} muss vor dem aktuellen Zeitpunkt liegen",
+	"lte.string":                    "{0} darf höchstens {1} Zeichen lang sein",
+	"lte.items":                     "{0} darf höchstens {1} Elemente enthalten",
+	"lte.number":                    "{0} muss {1} oder kleiner sein",
+	"lte.time":                      "{0} darf nicht nach dem aktuellen Zeitpunkt liegen",
+	"gt.string":                     "{0} muss mehr als {1} Zeichen lang sein",
+	"gt.items":                      "{0} muss mehr als {1} Elemente enthalten",
+	"gt.number":                     "{0} muss größer als {1} sein",
+	"gt.time":                       "{0} muss nach dem aktuellen Zeitpunkt liegen",
+	"gte.string":                    "{0} muss mindestens {1} Zeichen lang sein",
+	"gte.items":                     "{0} muss mindestens {1} Elemente enthalten",
+	"gte.number":                    "{0} muss {1} oder größer sein",
+	"gte.time":                      "{0} darf nicht vor dem aktuellen Zeitpunkt liegen",
+	"eqfield":                       "{0} muss gleich {1} sein",
+	"eqcsfield":                     "{0} muss gleich {1} sein",
+	"necsfield":                     "{0} darf nicht gleich {1} sein",
+	"gtcsfield":                     "{0} muss größer als {1} sein",
+	"gtecsfield":                    "{0} muss größer oder gleich {1} sein",
+	"ltcsfield":                     "{0} muss kleiner als {1} sein",
+	"ltecsfield":                    "{0} muss kleiner oder gleich {1} sein",
+	"nefield":                       "{0} darf nicht gleich {1} sein",
+	"gtefield":                      "{0} muss größer oder gleich {1} sein",
+	"gtfield":                       "{0} muss größer als {1} sein",
+	"ltefield":                      "{0} muss kleiner oder gleich {1} sein",
+	"ltfield":                       "{0} muss kleiner als {1} sein",
+	"fieldcontains":                 "{0} muss den Wert von {1} enthalten",
+	"fieldexcludes":                 "{0} darf den Wert von {1} nicht enthalten",
+	"alpha":                         "{0} darf nur Buchstaben enthalten",
+	"alphanum":                      "{0} darf nur Buchstaben und Ziffern enthalten",
+	"alphaunicode":                  "{0} darf nur Unicode-Buchstaben enthalten",
+	"alphanumunicode":               "{0} darf nur Unicode-Buchstaben und -Ziffern enthalten",
+	"numeric":                       "{0} muss ein gültiger numerischer Wert sein",
+	"number":                        "{0} muss eine gültige Zahl sein",
+	"hexadecimal":                   "{0} muss eine gültige Hexadezimalzahl sein",
+	"hexcolor":                      "{0} muss eine gültige HEX-Farbe sein",
+	"rgb":                           "{0} muss eine gültige RGB-Farbe sein",
+	"rgba":                          "{0} muss eine gültige RGBA-Farbe sein",
+	"hsl":                           "{0} muss eine gültige HSL-Farbe sein",
+	"hsla":                          "{0} muss eine gültige HSLA-Farbe sein",
+	"iscolor":                       "{0} muss eine gültige Farbe sein",
+	"e164":                          "{0} muss eine gültige Telefonnummer im E.164-Format sein",
+	"email":                         "{0} muss eine gültige E-Mail-Adresse sein",
+	"url":                           "{0} muss eine gültige URL sein",
+	"uri":                           "{0} muss eine gültige URI sein",
+	"urn_rfc2141":                   "{0} muss eine gültige URN nach RFC 2141 sein",
+	"file":                          "{0} muss eine existierende Datei sein",
+	"base64":                        "{0} muss ein gültiger Base64-String sein",
+	"base64url":                     "{0} muss ein gültiger Base64-URL-String sein",
+	"contains":                      "{0} muss den Text '{1}' enthalten",
+	"containsany":                   "{0} muss mindestens eines der Zeichen '{1}' enthalten",
+	"containsrune":                  "{0} muss das Zeichen '{1}' enthalten",
+	"excludes":                      "{0} darf den Text '{1}' nicht enthalten",
+	"excludesall":                   "{0} darf keines der Zeichen '{1}' enthalten",
+	"excludesrune":                  "{0} darf das Zeichen '{1}' nicht enthalten",
+	"startswith":                    "{0} muss mit '{1}' beginnen",
+	"endswith":                      "{0} muss mit '{1}' enden",
+	"startsnotwith":                 "{0} darf nicht mit '{1}' beginnen",
+	"endsnotwith":                   "{0} darf nicht mit '{1}' enden",
+	"isbn":                          "{0} muss eine gültige ISBN sein",
+	"isbn10":                        "{0} muss eine gültige ISBN-10 sein",
+	"isbn13":                        "{0} muss eine gültige ISBN-13 sein",
+	"eth_addr":                      "{0} muss eine gültige Ethereum-Adresse sein",
+	"btc_addr":                      "{0} muss eine gültige Bitcoin-Adresse sein",
+	"btc_addr_bech32":               "{0} muss eine gültige Bech32-Bitcoin-Adresse sein",
+	"uuid":                          "{0} muss eine gültige UUID sein",
+	"uuid3":                         "{0} muss eine gültige UUID der Version 3 sein",
+	"uuid4":                         "{0} muss eine gültige UUID der Version 4 sein",
+	"uuid5":                         "{0} muss eine gültige UUID der Version 5 sein",
+	"uuid_rfc4122":                  "{0} muss eine gültige UUID nach RFC 4122 sein",
+	"uuid3_rfc4122":                 "{0} muss eine gültige UUID der Version 3 nach RFC 4122 sein",
+	"uuid4_rfc4122":                 "{0} muss eine gültige UUID der Version 4 nach RFC 4122 sein",
+	"uuid5_rfc4122":                 "{0} muss eine gültige UUID der Version 5 nach RFC 4122 sein",
+	"ascii":                         "{0} darf nur ASCII-Zeichen enthalten",
+	"printascii":                    "{0} darf nur druckbare ASCII-Zeichen enthalten",
+	"multibyte":                     "{0} muss Multibyte-Zeichen enthalten",
+	"datauri":                       "{0} muss eine gültige Data-URI enthalten",
+	"latitude":                      "{0} muss einen gültigen Breitengrad enthalten",
+	"longitude":                     "{0} muss einen gültigen Längengrad enthalten",
+	"ssn":                           "{0} muss eine gültige SSN sein",
+	"ipv4":                          "{0} muss eine gültige IPv4-Adresse sein",
+	"ipv6":                          "{0} muss eine gültige IPv6-Adresse sein",
+	"ip":                            "{0} muss eine gültige IP-Adresse sein",
+	"cidrv4":                        "{0} muss eine gültige IPv4-CIDR-Notation enthalten",
+	"cidrv6":                        "{0} muss eine gültige IPv6-CIDR-Notation enthalten",
+	"cidr":                          "{0} muss eine gültige CIDR-Notation enthalten",
+	"tcp4_addr":                     "{0} muss eine gültige IPv4-TCP-Adresse sein",
+	"tcp6_addr":                     "{0} muss eine gültige IPv6-TCP-Adresse sein",
+	"tcp_addr":                      "{0} muss eine gültige TCP-Adresse sein",
+	"udp4_addr":                     "{0} muss eine gültige IPv4-UDP-Adresse sein",
+	"udp6_addr":                     "{0} muss eine gültige IPv6-UDP-Adresse sein",
+	"udp_addr":                      "{0} muss eine gültige UDP-Adresse sein",
+	"ip4_addr":                      "{0} muss eine auflösbare IPv4-Adresse sein",
+	"ip6_addr":                      "{0} muss eine auflösbare IPv6-Adresse sein",
+	"ip_addr":                       "{0} muss eine auflösbare IP-Adresse sein",
+	"unix_addr":                     "{0} muss eine auflösbare UNIX-Adresse sein",
+	"mac":                           "{0} muss eine gültige MAC-Adresse enthalten",
+	"hostname":                      "{0} muss ein gültiger Hostname nach RFC 952 sein",
+	"hostname_rfc1123":              "{0} muss ein gültiger Hostname nach RFC 1123 sein",
+	"fqdn":                          "{0} muss ein gültiger FQDN sein",
+	"unique":                        "{0} muss eindeutige Werte enthalten",
+	"oneof":                         "{0} muss einer der Werte [{1}] sein",
+	"html":                          "{0} muss HTML enthalten",
+	"html_encoded":                  "{0} muss HTML-kodiert sein",
+	"url_encoded":                   "{0} muss URL-kodiert sein",
+	"dir":                           "{0} muss ein existierendes Verzeichnis sein",
+	"json":                          "{0} muss ein gültiger JSON-String sein",
+	"hostname_port":                 "{0} muss ein gültiges host:port sein",
+	"lowercase":                     "{0} darf nur Kleinbuchstaben enthalten",
+	"uppercase":                     "{0} darf nur Großbuchstaben enthalten",
+	"datetime":                      "{0} entspricht nicht dem Format {1}",
+	"timezone":                      "{0} muss eine gültige Zeitzone sein",
+	"iso3166_1_alpha2":              "{0} muss ein gültiger Ländercode nach ISO 3166-1 alpha-2 sein",
+	"iso3166_1_alpha3":              "{0} muss ein gültiger Ländercode nach ISO 3166-1 alpha-3 sein",
+	"iso3166_1_alpha_numeric":       "{0} muss ein gültiger numerischer Ländercode nach ISO 3166-1 sein",
+	"country_code":                  "{0} muss ein gültiger Ländercode nach ISO 3166-1 sein",
+	"bcp47_language_tag":            "{0} muss ein gültiges Sprach-Tag nach BCP 47 sein",
+	"postcode_iso3166_alpha2":       "{0} entspricht nicht dem Postleitzahlenformat des Landes {1}",
+	"postcode_iso3166_alpha2_field": "{0} entspricht nicht dem Postleitzahlenformat des Landes in {1}",
+	"bic":                           "{0} muss ein gültiger BIC (ISO 9362) sein",
+
+	// binding tags
+	"maxsize":     "{0} darf höchstens {1} groß sein",
+	"contenttype": "{0} muss vom Typ [{1}] sein",
+}
@@ -0,0 +1,183 @@
+package i18n
+
+// English are the messages of the fallback language of the Default catalog.
+//
+// Validation messages are keyed by validator tag, {0} is the field and {1} the parameter of
+// the tag. Tags whose message depends on the kind of the field have a message per kind,
+// eg. min.string, min.items and min.number. Status messages are keyed by StatusKey and
+// StatusNamedKey of the status reason, {0} is the qualified kind and {1} the name of the
+// object. English status messages are missing on purpose, so that the messages of
+// metav1.Status are kept as is.
+var English = Messages{
+	// binding errors
+	KeyInvalid:      "{0} is invalid",
+	KeyInvalidNamed: "{0} \"{1}\" is invalid",
+	KeyDecode:       "failed to decode into {0}",
+	KeyDecodeNamed:  "failed to decode into {0} \"{1}\"",
+	KeyDecodeType:   "{0} must be of type {1}",
+	KeyUnknownField: "unknown field \"{0}\"",
+	KeyValidation:   "{0} failed on the {1} validation",
+
+	// request bodies
+	KeyBodyEmpty:     "the request body is empty",
+	KeyBodyTruncated: "the request body ends unexpectedly",
+	KeyBodySyntax:    "the request body is not valid JSON: {0}",
+	KeyBodyPosition:  "{0} at line {1}, column {2}",
+	KeyBodyFormat:    "the request body is not valid {0}: {1}",
+	KeyBodyLine:      "{0} at line {1}",
+	KeyItemsInvalid:  "the {0} items {1} are invalid",
+
+	// errors
+	KeyRedacted: "{0} (request ID {1})",
+
+	// validator tags
+	"required":                      "{0} is a required field",
+	"required_if":                   "{0} is a required field",
+	"required_unless":               "{0} is a required field",
+	"required_with":                 "{0} is a required field",
+	"required_with_all":             "{0} is a required field",
+	"required_without":              "{0} is a required field",
+	"required_without_all":          "{0} is a required field",
+	"excluded_with":                 "{0} must not be set",
+	"excluded_with_all":             "{0} must not be set",
+	"excluded_without":              "{0} must not be set",
+	"excluded_without_all":          "{0} must not be set",
+	"isdefault":                     "{0} must be empty",
+	"len.string":                    "{0} must be {1} characters long",
+	"len.items":                     "{0} must contain {1} items",
+	"len.number":                    "{0} must be equal to {1}",
+	"min.string":                    "{0} must be at least {1} characters long",
+	"min.items":                     "{0} must contain at least {1} items",
+	"min.number":                    "{0} must be {1} or greater",
+	"max.string":                    "{0} must be at most {1} characters long",
+	"max.items":                     "{0} must contain at most {1} items",
+	"max.number":                    "{0} must be {1} or less",
+	"eq":                            "{0} is not equal to {1}",
+	"ne":                            "{0} must not be equal to {1}",
+	"lt.string":                     "{0} must be less than {1} characters long",
+	"lt.items":                      "{0} must contain less than {1} items",
+	"lt.number":                     "{0} must be less than {1}",
+	"lt.time":                       "{0} must be before the current date and time",
+	"lte.string":                    "{0} must be at most {1} characters long",
+	"lte.items":                     "{0} must contain at most {1} items",
+	"lte.number":                    "{0} must be {1} or less",
+	"lte.time":                      "{0} must not be after the current date and time",
+	"gt.string":                     "{0} must be more than {1} characters long",
+	"gt.items":                      "{0} must contain more than {1} items",
+	"gt.number":                     "{0} must be greater than {1}",
+	"gt.time":                       "{0} must be after the current date and time",
+	"gte.string":                    "{0} must be at least {1} characters long",
+	"gte.items":                     "{0} must contain at least {1} items",
+	"gte.number":                    "{0} must be {1} or greater",
+	"gte.time":                      "{0} must not be before the current date and time",
+	"eqfield":                       "{0} must be equal to {1}",
+	"eqcsfield":                     "{0} must be equal to {1}",
+	"necsfield":                     "{0} must not be equal to {1}",
+	"gtcsfield":                     "{0} must be greater than {1}",
+	"gtecsfield":                    "{0} must be greater than or equal to {1}",
+	"ltcsfield":                     "{0} must be less than {1}",
+	"ltecsfield":                    "{0} must be less than or equal to {1}",
+	"nefield":                       "{0} must not be equal to {1}",
+	"gtefield":                      "{0} must be greater than or equal to {1}",
+	"gtfield":                       "{0} must be greater than {1}",
+	"ltefield":                      "{0} must be less than or equal to {1}",
+	"ltfield":                       "{0} must be less than {1}",
+	"fieldcontains":                 "{0} must contain the value of {1}",
+	"fieldexcludes":                 "{0} must not contain the value of {1}",
+	"alpha":                         "{0} can only contain alphabetic characters",
+	"alphanum":                      "{0} can only contain alphanumeric characters",
+	"alphaunicode":                  "{0} can only contain unicode letters",
+	"alphanumunicode":               "{0} can only contain unicode letters and numbers",
+	"numeric":                       "{0} must be a valid numeric value",
+	"number":                        "{0} must be a valid number",
+	"hexadecimal":                   "{0} must be a valid hexadecimal",
+	"hexcolor":                      "{0} must be a valid HEX color",
+	"rgb":                           "{0} must be a valid RGB color",
+	"rgba":                          "{0} must be a valid RGBA color",
+	"hsl":                           "{0} must be a valid HSL color",
+	"hsla":                          "{0} must be a valid HSLA color",
+	"iscolor":                       "{0} must be a valid color",
+	"e164":                          "{0} must be a valid E.164 formatted phone number",
+	"email":                         "{0} must be a valid email address",
+	"url":                           "{0} must be a valid URL",
+	"uri":                           "{0} must be a valid URI",
+	"urn_rfc2141":                   "{0} must be a valid RFC 2141 URN",
+	"file":                          "{0} must be an existing file",
+	"base64":                        "{0} must be a valid Base64 string",
+	"base64url":                     "{0} must be a valid Base64 URL string",
+	"contains":                      "{0} must contain the text '{1}'",
+	"containsany":                   "{0} must contain at least one of the characters '{1}'",
+	"containsrune":                  "{0} must contain the character '{1}'",
+	"excludes":                      "{0} cannot contain the text '{1}'",
+	"excludesall":                   "{0} cannot contain any of the characters '{1}'",
+	"excludesrune":                  "{0} cannot contain the character '{1}'",
+	"startswith":                    "{0} must start with '{1}'",
+	"endswith":                      "{0} must end with '{1}'",
+	"startsnotwith":                 "{0} must not start with '{1}'",
+	"endsnotwith":                   "{0} must not end with '{1}'",
+	"isbn":                          "{0} must be a valid ISBN number",
+	"isbn10":                        "{0} must be a valid ISBN-10 number",
+	"isbn13":                        "{0} must be a valid ISBN-13 number",
+	"eth_addr":                      "{0} must be a valid Ethereum address",
+	"btc_addr":                      "{0} must be a valid Bitcoin address",
+	"btc_addr_bech32":               "{0} must be a valid Bech32 Bitcoin address",
+	"uuid":                          "{0} must be a valid UUID",
+	"uuid3":                         "{0} must be a valid version 3 UUID",
+	"uuid4":                         "{0} must be a valid version 4 UUID",
+	"uuid5":                         "{0} must be a valid version 5 UUID",
+	"uuid_rfc4122":                  "{0} must be a valid RFC 4122 UUID",
+	"uuid3_rfc4122":                 "{0} must be a valid version 3 RFC 4122 UUID",
+	"uuid4_rfc4122":                 "{0} must be a valid version 4 RFC 4122 UUID",
+	"uuid5_rfc4122":                 "{0} must be a valid version 5 RFC 4122 UUID",
+	"ascii":                         "{0} must contain only ASCII characters",
+	"printascii":                    "{0} must contain only printable ASCII characters",
+	"multibyte":                     "{0} must contain multibyte characters",
+	"datauri":                       "{0} must contain a valid Data URI",
+	"latitude":                      "{0} must contain valid latitude coordinates",
+	"longitude":                     "{0} must contain valid longitude coordinates",
+	"ssn":                           "{0} must be a valid SSN number",
+	"ipv4":                          "{0} must be a valid IPv4 address",
+	"ipv6":                          "{0} must be a valid IPv6 address",
+	"ip":                            "{0} must be a valid IP address",
+	"cidrv4":                        "{0} must contain a valid IPv4 CIDR notation",
+	"cidrv6":                        "{0} must contain a valid IPv6 CIDR notation",
+	"cidr":                          "{0} must contain a valid CIDR notation",
+	"tcp4_addr":                     "{0} must be a valid IPv4 TCP address",
+	"tcp6_addr":                     "{0} must be a valid IPv6 TCP address",
+	"tcp_addr":                      "{0} must be a valid TCP address",
+	"udp4_addr":                     "{0} must be a valid IPv4 UDP address",
+	"udp6_addr":                     "{0} must be a valid IPv6 UDP address",
+	"udp_addr":                      "{0} must be a valid UDP address",
+	"ip4_addr":                      "{0} must be a resolvable IPv4 address",
+	"ip6_addr":                      "{0} must be a resolvable IPv6 address",
+	"ip_addr":                       "{0} must be a resolvable IP address",
+	"unix_addr":                     "{0} must be a resolvable UNIX address",
+	"mac":                           "{0} must contain a valid MAC address",
+	"hostname":                      "{0} must be a valid RFC 952 hostname",
+	"hostname_rfc1123":              "{0} must be a valid RFC 1123 hostname",
+	"fqdn":                          "{0} must be a valid FQDN",
+	"unique":                        "{0} must contain unique values",
+	"oneof":                         "{0} must be one of [{1}]",
+	"html":                          "{0} must contain HTML",
+	"html_encoded":                  "{0} must be HTML encoded",
+	"url_encoded":                   "{0} must be URL encoded",
+	"dir":                           "{0} must be an existing directory",
+	"json":                          "{0} must be a valid JSON string",
+	"hostname_port":                 "{0} must be a valid host:port",
+	"lowercase":                     "{0} must be a lowercase string",
+	"uppercase":                     "{0} must be an uppercase string",
+	"datetime":                      "{0} does not match the {1} format",
+	"timezone":                      "{0} must be a valid time zone",
+	"iso3166_1_alpha2":              "{0} must be a valid ISO 3166-1 alpha-2 country code",
+	"iso3166_1_alpha3":              "{0} must be a valid ISO 3166-1 alpha-3 country code",
+	"iso3166_1_alpha_numeric":       "{0} must be a valid ISO 3166-1 numeric country code",
+	"country_code":                  "{0} must be a valid ISO 3166-1 country code",
+	"bcp47_language_tag":            "{0} must be a valid BCP 47 language tag",
+	"postcode_iso3166_alpha2":       "{0} does not match the postcode format of country {1}",
+	"postcode_iso3166_alpha2_field": "{0} does not match the postcode format of the country in {1}",
+	"bic":                           "{0} must be a valid BIC (ISO 9362)",
+
+	// binding tags
+	"maxsize":     "{0} must be at most {1}",
+	"contenttype": "{0} must be of type [{1}]",
+}
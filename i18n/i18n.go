@@ -0,0 +1,248 @@
+// Package i18n translates validation and status messages into the language of a request.
+// Catalogs are built on universal-translator. A language is a set of Messages keyed by message
+// key, eg. a validator tag, and new languages are shipped by registering their Messages.
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// Keys of the messages of binding errors.
+const (
+	// KeyInvalid is the message of an invalid object, {0} is its qualified kind.
+	KeyInvalid = "binding.invalid"
+	// KeyInvalidNamed is the message of an invalid object, {0} is its qualified kind and {1} its name.
+	KeyInvalidNamed = "binding.invalid.named"
+	// KeyDecode is the message of a request that can't be decoded into an object of kind {0}.
+	KeyDecode = "binding.decode"
+	// KeyDecodeNamed is KeyDecode for objects with name {1}.
+	KeyDecodeNamed = "binding.decode.named"
+	// KeyDecodeValue is the message of a field {0} that can't be decoded. If it is missing,
+	// the error returned by the decoder is used.
+	KeyDecodeValue = "binding.decode.value"
+	// KeyDecodeType is the message of a field {0} whose value is not of the JSON type {1}, eg. number.
+	KeyDecodeType = "binding.decode.type"
+	// KeyUnknownField is the message of a field {0} that doesn't exist in the object bound to a request.
+	KeyUnknownField = "binding.unknown"
+	// KeyValidation is the message of validator tags without a message, {0} is the field and {1} the tag.
+	KeyValidation = "binding.validation"
+	// KeyBodyEmpty is the message of an empty request body.
+	KeyBodyEmpty = "binding.body.empty"
+	// KeyBodyTruncated is the message of a request body that ends in the middle of a value.
+	KeyBodyTruncated = "binding.body.truncated"
+	// KeyBodySyntax is the message of a request body that is not valid JSON, {0} is the error of the decoder.
+	KeyBodySyntax = "binding.body.syntax"
+	// KeyBodyPosition adds the position of an error to a message of a request body, {0} is the
+	// message, {1} the line and {2} the column.
+	KeyBodyPosition = "binding.body.position"
+	// KeyBodyFormat is the message of a request body in another format than JSON with a syntax
+	// error, {0} is the name of the format, eg. YAML, and {1} the error of the decoder.
+	KeyBodyFormat = "binding.body.format"
+	// KeyBodyLine adds the line of an error to a message of a request body, {0} is the message
+	// and {1} the line.
+	KeyBodyLine = "binding.body.line"
+	// KeyItemsInvalid is the message of a list of items of kind {0} with invalid items, {1} are
+	// their indexes, eg. [1], [4].
+	KeyItemsInvalid = "binding.items.invalid"
+)
+
+// KeyRedacted is the message of an error whose message is hidden in production mode, {0} is the
+// message of its status, eg. Internal Server Error, and {1} the ID of the request.
+const KeyRedacted = "error.redacted"
+
+// StatusKey returns the key of the message of a metav1.Status with reason, eg. status.NotFound.
+// {0} is the qualified kind of the details of the status.
+func StatusKey(reason string) string {
+	return "status." + reason
+}
+
+// StatusNamedKey is StatusKey for statuses whose details have a name, {1} is the name,
+// eg. status.NotFound.named.
+func StatusNamedKey(reason string) string {
+	return StatusKey(reason) + ".named"
+}
+
+// Messages are the translations of a language keyed by message key. Messages may contain the
+// parameters {0} and {1}, which must appear in that order, eg. "{0} must be at least {1} characters long".
+type Messages map[string]string
+
+// Catalog holds the messages of all supported languages. Register isn't synchronized with the
+// translations of requests, so a catalog must be fully populated before requests are served.
+type Catalog struct {
+	uni      *ut.UniversalTranslator
+	fallback ut.Translator
+}
+
+// NewCatalog returns a catalog with messages in the fallback language lang. Messages missing
+// from other languages are looked up in the fallback language.
+func NewCatalog(lang string, messages Messages) (*Catalog, error) {
+	l := newLocale(lang)
+	uni := ut.New(l, l)
+	c := &Catalog{uni: uni, fallback: uni.GetFallback()}
+	if err := add(c.fallback, messages); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Register adds the messages of lang, eg. "de" or "pt-BR", to the catalog. Messages of
+// a language that is already registered are overridden.
+func (c *Catalog) Register(lang string, messages Messages) error {
+	trans, found := c.uni.GetTranslator(lang)
+	if !found {
+		if err := c.uni.AddTranslator(newLocale(lang), false); err != nil {
+			return err
+		}
+		trans, _ = c.uni.GetTranslator(lang)
+	}
+	return add(trans, messages)
+}
+
+func add(trans ut.Translator, messages Messages) error {
+	for key, text := range messages {
+		if err := trans.Add(key, text, true); err != nil {
+			return fmt.Errorf("i18n: invalid message %q for locale %s: %w", key, trans.Locale(), err)
+		}
+	}
+	return nil
+}
+
+// Fallback returns the translator of the fallback language.
+func (c *Catalog) Fallback() ut.Translator {
+	return c.fallback
+}
+
+// Translator returns the translator of the first supported language in langs. A language
+// is also matched by its base language, eg. de-AT matches de. If none of the languages
+// are supported, the translator of the fallback language is returned.
+func (c *Catalog) Translator(langs ...string) ut.Translator {
+	for _, lang := range langs {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" || lang == "*" {
+			continue
+		}
+		if trans, found := c.uni.GetTranslator(lang); found {
+			return trans
+		}
+		if i := strings.IndexAny(lang, "-_"); i > 0 {
+			if trans, found := c.uni.GetTranslator(lang[:i]); found {
+				return trans
+			}
+		}
+	}
+	return c.fallback
+}
+
+// FromRequest returns the translator of the language preferred by the Accept-Language header of r.
+func (c *Catalog) FromRequest(r *http.Request) ut.Translator {
+	if r == nil {
+		return c.fallback
+	}
+	return c.Translator(ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+}
+
+// T translates key with params. Keys missing from the language of trans are looked up in the
+// fallback language. ok is false if the key is missing from both.
+func (c *Catalog) T(trans ut.Translator, key string, params ...string) (msg string, ok bool) {
+	if trans == nil {
+		trans = c.fallback
+	}
+	// ut panics if a message has more parameters than passed
+	params = append(params, "", "")
+	if msg, err := trans.T(key, params...); err == nil {
+		return msg, true
+	}
+	if msg, err := c.fallback.T(key, params...); err == nil {
+		return msg, true
+	}
+	return "", false
+}
+
+// Default is the catalog used when no catalog is given. It supports English, which is
+// the fallback language, and German.
+var Default = func() *Catalog {
+	c, err := NewCatalog("en", English)
+	if err != nil {
+		panic(err)
+	}
+	if err := c.Register("de", German); err != nil {
+		panic(err)
+	}
+	return c
+}()
+
+// Register adds the messages of lang to the Default catalog.
+func Register(lang string, messages Messages) error {
+	return Default.Register(lang, messages)
+}
+
+// FromRequest returns the translator of the language preferred by r from the Default catalog.
+func FromRequest(r *http.Request) ut.Translator {
+	return Default.FromRequest(r)
+}
+
+// T translates key using the Default catalog.
+func T(trans ut.Translator, key string, params ...string) (string, bool) {
+	return Default.T(trans, key, params...)
+}
+
+// ParseAcceptLanguage returns the language ranges of an Accept-Language header ordered by
+// their q-value, eg. "de-AT, en;q=0.5, fr;q=0.8" returns [de-AT fr en]. Ranges with q=0 are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		l := lang{tag: strings.TrimSpace(fields[0]), q: 1}
+		if l.tag == "" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+				l.q = q
+			}
+		}
+		if l.q > 0 {
+			langs = append(langs, l)
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+// locale is a locales.Translator that only supports plain messages, ie. ut.Translator.Add
+// and T. Number, date and currency formatting are not supported, since the vendored
+// go-playground/locales does not include locale data.
+type locale struct {
+	locales.Translator
+	name string
+}
+
+func newLocale(name string) locale {
+	return locale{name: strings.ToLower(name)}
+}
+
+func (l locale) Locale() string                        { return l.name }
+func (l locale) PluralsCardinal() []locales.PluralRule { return nil }
+func (l locale) PluralsOrdinal() []locales.PluralRule  { return nil }
+func (l locale) PluralsRange() []locales.PluralRule    { return nil }
@@ -3,86 +3,186 @@ package main
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"github.com/unrolled/render"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"os"
 	"path/filepath"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"go.wandrs.dev/binding"
-	"go.wandrs.dev/inject"
+	"github.com/go-playground/validator/v10"
+	"github.com/tamalsaha/learn-chi/binding"
+	"github.com/tamalsaha/learn-chi/binding/decoders/cbor"
+	"github.com/tamalsaha/learn-chi/binding/decoders/msgpack"
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/i18n"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2/klogr"
 )
 
+//go:generate go run ./cmd/fastinvoker-gen
+
 type User struct {
 	Name string
 }
 
 func main() {
+	// error messages and internals are only shown to clients in development
+	development := os.Getenv("APP_ENV") == "development"
+	responsewriters.ProductionMode = !development
+
+	binding.RegisterValidation("namespace_exists", namespaceExists)
+	i18n.Register("en", i18n.Messages{"namespace_exists": "{0} must name an existing namespace"})
+	cbor.Register()
+	msgpack.Register()
+
+	app := binding.NewScope(nil).
+		Provide(createKubeClient, binding.Singleton).
+		Provide(createNodeClient, binding.PerRequest)
+
 	r := chi.NewRouter()
-	r.Use(middleware.RequestID)
-	r.Use(middleware.Logger)
+	r.Use(chim.RequestID)
+	r.Use(chim.NewLogr(klogr.New().WithName("learn-chi"), nil))
 	r.Use(middleware.Recoverer)
 	r.Use(binding.Injector(render.New()))
+	r.Use(binding.WithScope(app))
+	r.Use(binding.WithErrorPages(binding.ErrorPages{
+		Templates: map[int]string{
+			http.StatusNotFound:            "errors/404",
+			http.StatusUnprocessableEntity: "errors/422",
+			http.StatusInternalServerError: "errors/500",
+		},
+		Default:     "errors/default",
+		Development: development,
+	}))
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("hello world"))
 	})
-	r.Get("/inject", binding.Handler(hello))
+	r.With(binding.Params(HelloParams{})).Get("/inject", binding.Handler(hello))
+	r.With(binding.WithFieldValidation(binding.FieldValidationWarn), binding.Bind(Greeting{})).Post("/greet", binding.Handler(greet))
+	r.With(binding.WithMaxBodySize(10<<20), binding.JSONStream(Greeting{})).Post("/greetings", binding.Handler(greetAll))
+	r.With(binding.WithMultipartLimits(binding.MultipartLimits{MaxMemory: 1 << 20, MaxSize: 20 << 20}), binding.Form(Upload{})).Post("/upload", binding.Handler(upload))
+
+	r.Route("/k8s", func(r chi.Router) {
+		r.Use(binding.WithScope(binding.NewScope(app).Map(User{
+			Name: "John",
+		})))
+		r.Get("/", binding.Handler(k8s))
+		r.Route("/nodes", binding.Controller(&NodeController{}))
+		r.With(binding.Params(PodParams{})).Get("/pods", binding.Handler(listPods))
+	})
 
-	r.With(binding.Inject(createKubeClient), binding.Map(User{
-		Name: "John",
-	})).Get("/k8s", binding.Handler(k8s))
+	if err := binding.Verify(r); err != nil {
+		log.Fatalln(err)
+	}
 
 	log.Println("running server on :3333")
 	http.ListenAndServe(":3333", r)
 }
 
-func hello(r *http.Request) string {
-	return "hello " + r.URL.Query().Get("name")
+type HelloParams struct {
+	Name string `query:"name" validate:"required"`
+}
+
+func hello(p HelloParams) string {
+	return "hello " + p.Name
+}
+
+type Greeting struct {
+	Name     string `json:"name" xml:"name" validate:"required"`
+	Language string `json:"language" xml:"language" validate:"omitempty,oneof=en de"`
+}
+
+func greet(g Greeting) string {
+	if g.Language == "de" {
+		return "hallo " + g.Name
+	}
+	return "hello " + g.Name
+}
+
+func greetAll(items *binding.Items) ([]string, error) {
+	var greetings []string
+	var g Greeting
+	for items.Next(&g) {
+		greetings = append(greetings, greet(g))
+	}
+	if err := items.Err(); err != nil {
+		return nil, err
+	}
+	return greetings, nil
+}
+
+type Upload struct {
+	Title string                `form:"title"`
+	File  *multipart.FileHeader `form:"file" validate:"required,maxsize=10Mi"`
+}
+
+func upload(u Upload) string {
+	return fmt.Sprintf("received %s (%d bytes) as %q", u.File.Filename, u.File.Size, u.Title)
 }
 
-func k8s(kc kubernetes.Interface, nodeclient corev1.NodeInterface, u User) []byte {
+func k8s(kc kubernetes.Interface, u User) ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteString("hello " + u.Name)
 	buf.WriteRune('\n')
 
 	info, err := kc.Discovery().ServerVersion()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	buf.WriteString("k8s version = " + info.GitVersion)
 	buf.WriteRune('\n')
+	return buf.Bytes(), nil
+}
 
-	nodes, err := nodeclient.List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		panic(err)
-	}
-	buf.WriteString("Nodes: \n")
-	for _, n := range nodes.Items {
-		buf.WriteString(n.Name)
-		buf.WriteRune('\n')
-	}
-	return buf.Bytes()
+// NodeController serves /k8s/nodes. Its fields are injected for every request.
+type NodeController struct {
+	Nodes corev1.NodeInterface `inject:""`
 }
 
-func createKubeClient(injector inject.Injector) {
+func (c *NodeController) List(ctx context.Context) (*core.NodeList, error) {
+	return c.Nodes.List(ctx, metav1.ListOptions{})
+}
+
+func (c *NodeController) Get(ctx context.Context, r *http.Request) (*core.Node, error) {
+	return c.Nodes.Get(ctx, chi.URLParam(r, "name"), metav1.GetOptions{})
+}
+
+type PodParams struct {
+	Namespace string `query:"namespace" default:"default" validate:"namespace_exists"`
+}
+
+func listPods(ctx context.Context, kc kubernetes.Interface, p PodParams) (*core.PodList, error) {
+	return kc.CoreV1().Pods(p.Namespace).List(ctx, metav1.ListOptions{})
+}
+
+// namespaceExists validates that the namespace named by a field exists.
+func namespaceExists(ctx context.Context, fl validator.FieldLevel, kc kubernetes.Interface) bool {
+	_, err := kc.CoreV1().Namespaces().Get(ctx, fl.Field().String(), metav1.GetOptions{})
+	return err == nil
+}
+
+func createKubeClient() (kubernetes.Interface, error) {
 	masterURL := ""
 	kubeconfigPath := filepath.Join(homedir.HomeDir(), ".kube", "config")
 
 	config, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfigPath)
 	if err != nil {
-		log.Fatalf("Could not get Kubernetes config: %s", err)
+		return nil, fmt.Errorf("could not get Kubernetes config: %w", err)
 	}
+	return kubernetes.NewForConfig(config)
+}
 
-	var client kubernetes.Interface = kubernetes.NewForConfigOrDie(config)
-	injector.Map(client)
-
-	var nodeclient corev1.NodeInterface = client.CoreV1().Nodes()
-	injector.Map(nodeclient)
+func createNodeClient(client kubernetes.Interface) corev1.NodeInterface {
+	return client.CoreV1().Nodes()
 }
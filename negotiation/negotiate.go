@@ -0,0 +1,229 @@
+// Package negotiation selects the media type of a response based on the Accept header of the request.
+//
+// Modelled after k8s.io/apiserver/pkg/endpoints/handlers/negotiation
+package negotiation
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Negotiator picks a Serializer for a response from an ordered list of Serializers.
+type Negotiator struct {
+	serializers []Serializer
+}
+
+// New returns a Negotiator for the given Serializers. The first Serializer that can encode
+// an object is used when the request does not have an Accept header.
+func New(serializers ...Serializer) *Negotiator {
+	return &Negotiator{serializers: serializers}
+}
+
+// Default negotiates between JSON, YAML, XML, plain text and protobuf, both the protobuf of the
+// Kubernetes API and plain protobuf messages.
+var Default = New(JSON, YAML, XML, Text, KubernetesProtobuf, Protobuf)
+
+// Serializers returns the Serializers known to the Negotiator.
+func (n *Negotiator) Serializers() []Serializer {
+	return n.serializers
+}
+
+// MediaTypes returns the media types supported by the Negotiator.
+func (n *Negotiator) MediaTypes() []string {
+	types := make([]string, 0, len(n.serializers))
+	for _, s := range n.serializers {
+		types = append(types, s.MediaType())
+	}
+	return types
+}
+
+// NegotiateOutputMediaType returns the Serializer that should be used to write obj
+// in response to req. The Serializer with the highest quality in the Accept header wins.
+// Ties are broken by the specificity of the matching media range, then by the order of the
+// media ranges in the Accept header and finally by the order of the Serializers.
+// Strings are written as plain text unless the client explicitly asks for another media type.
+// If none of the accepted media types can encode obj, a NotAcceptable error is returned.
+//
+// A Serializer may still fail to encode obj, eg. XML of a struct with a map field. Use Encode
+// to fall back to the next acceptable Serializer in that case.
+func (n *Negotiator) NegotiateOutputMediaType(req *http.Request, obj interface{}) (Serializer, error) {
+	candidates := n.candidates(req, obj)
+	if len(candidates) == 0 {
+		return nil, NewNotAcceptableError(n.MediaTypes())
+	}
+	return candidates[0], nil
+}
+
+// Encode encodes obj with the Serializer NegotiateOutputMediaType returns. If it fails, the
+// acceptable Serializers are tried in the order of their preference. If none of them can
+// encode obj, a NotAcceptable error is returned.
+func (n *Negotiator) Encode(req *http.Request, obj interface{}) (Serializer, []byte, error) {
+	var buf bytes.Buffer
+	for _, s := range n.candidates(req, obj) {
+		buf.Reset()
+		if err := s.Encode(&buf, obj); err == nil {
+			return s, buf.Bytes(), nil
+		}
+	}
+	return nil, nil, NewNotAcceptableError(n.MediaTypes())
+}
+
+// candidates returns the Serializers accepted by req that are able to encode obj, the
+// preferred one first.
+func (n *Negotiator) candidates(req *http.Request, obj interface{}) []Serializer {
+	clauses := ParseAccept(req.Header.Get("Accept"))
+	if len(clauses) == 0 {
+		clauses = []AcceptClause{{Type: "*", SubType: "*", Q: 1}}
+	}
+
+	var candidates []Serializer
+	var matches []match
+	for _, s := range n.preferred(obj) {
+		if !s.Accepts(obj) {
+			continue
+		}
+		if m := bestClause(clauses, s.MediaType()); m.q > 0 {
+			candidates = append(candidates, s)
+			matches = append(matches, m)
+		}
+	}
+	sort.Stable(byMatch{candidates, matches})
+	return candidates
+}
+
+// byMatch sorts Serializers by their match, the best first.
+type byMatch struct {
+	serializers []Serializer
+	matches     []match
+}
+
+func (b byMatch) Len() int           { return len(b.serializers) }
+func (b byMatch) Less(i, j int) bool { return b.matches[i].betterThan(b.matches[j]) }
+func (b byMatch) Swap(i, j int) {
+	b.serializers[i], b.serializers[j] = b.serializers[j], b.serializers[i]
+	b.matches[i], b.matches[j] = b.matches[j], b.matches[i]
+}
+
+// preferred returns the Serializers in the order they should be considered for obj.
+func (n *Negotiator) preferred(obj interface{}) []Serializer {
+	if _, ok := obj.(string); !ok {
+		return n.serializers
+	}
+	out := make([]Serializer, 0, len(n.serializers))
+	for _, s := range n.serializers {
+		if s.MediaType() == Text.MediaType() {
+			out = append(out, s)
+		}
+	}
+	for _, s := range n.serializers {
+		if s.MediaType() != Text.MediaType() {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+type match struct {
+	q           float64
+	specificity int
+	index       int
+}
+
+func (m match) betterThan(o match) bool {
+	if m.q != o.q {
+		return m.q > o.q
+	}
+	if m.specificity != o.specificity {
+		return m.specificity > o.specificity
+	}
+	return m.index < o.index
+}
+
+// bestClause returns the most specific clause matching mediaType. The quality of
+// a media type is defined by the most specific media range that matches it.
+func bestClause(clauses []AcceptClause, mediaType string) match {
+	m := match{specificity: -1}
+	for i, c := range clauses {
+		if !c.Matches(mediaType) {
+			continue
+		}
+		if sp := c.specificity(); sp > m.specificity {
+			m = match{q: c.Q, specificity: sp, index: i}
+		}
+	}
+	return m
+}
+
+// AcceptClause is one media range of an Accept header.
+type AcceptClause struct {
+	Type    string
+	SubType string
+	Q       float64
+	Params  map[string]string
+}
+
+// Matches returns true if mediaType is covered by the media range of the clause.
+func (c AcceptClause) Matches(mediaType string) bool {
+	typ, subType := splitMediaType(mediaType)
+	return (c.Type == "*" || c.Type == typ) && (c.SubType == "*" || c.SubType == subType)
+}
+
+func (c AcceptClause) specificity() int {
+	switch {
+	case c.Type == "*":
+		return 0
+	case c.SubType == "*":
+		return 1
+	default:
+		return 2 + len(c.Params)
+	}
+}
+
+// ParseAccept parses an Accept header into clauses in the order they appear in the header.
+// Invalid media ranges are dropped. A missing q-value is treated as 1 and an invalid one as 0.
+func ParseAccept(header string) []AcceptClause {
+	var clauses []AcceptClause
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		clause := AcceptClause{Q: 1, Params: map[string]string{}}
+		clause.Type, clause.SubType = splitMediaType(mediaType)
+		if clause.Type == "*" && clause.SubType != "*" {
+			continue
+		}
+		for k, v := range params {
+			if k == "q" {
+				q, err := strconv.ParseFloat(v, 64)
+				if err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+				clause.Q = q
+				continue
+			}
+			clause.Params[k] = v
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+func splitMediaType(mediaType string) (string, string) {
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) == 1 {
+		if parts[0] == "*" {
+			return "*", "*"
+		}
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,122 @@
+package negotiation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tamalsaha/learn-chi/negotiation"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+type greeting struct {
+	Name string
+}
+
+func TestNegotiateOutputMediaType(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	cases := []struct {
+		name   string
+		accept string
+		obj    interface{}
+		want   string // media type, empty if not acceptable
+	}{
+		{"missing Accept", "", greeting{}, "application/json"},
+		{"missing Accept string", "", "hello", "text/plain"},
+		{"exact", "application/yaml", greeting{}, "application/yaml"},
+		{"q-value", "application/json;q=0.5, application/yaml;q=0.8, application/xml;q=0.1", greeting{}, "application/yaml"},
+		{"missing q-value is 1", "application/json;q=0.9, application/xml", greeting{}, "application/xml"},
+		{"order of equal q-values", "application/xml, application/yaml", greeting{}, "application/xml"},
+		{"any", "*/*", greeting{}, "application/json"},
+		{"any string", "*/*", "hello", "text/plain"},
+		{"type wildcard", "text/*", "hello", "text/plain"},
+		{"type wildcard without serializer", "text/*", greeting{}, ""},
+		{"type wildcard order of serializers", "application/*", greeting{}, "application/json"},
+		{"more specific range wins", "application/*;q=0.5, application/yaml", greeting{}, "application/yaml"},
+		{"q of the most specific range", "application/*, application/json;q=0.2", greeting{}, "application/yaml"},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", greeting{}, "application/xml"},
+		{"q=0 excludes", "application/json;q=0, */*", greeting{}, "application/yaml"},
+		{"q=0 only", "application/json;q=0", greeting{}, ""},
+		{"q=0 wildcard", "*/*;q=0", greeting{}, ""},
+		{"invalid q-value", "application/json;q=2, application/yaml;q=0.1", greeting{}, "application/yaml"},
+		{"unsupported", "text/html", greeting{}, ""},
+		{"XML of structs only", "application/xml, application/json;q=0.5", []string{"a"}, "application/json"},
+		{"Kubernetes protobuf", "application/vnd.kubernetes.protobuf, application/json;q=0.9", pod, "application/vnd.kubernetes.protobuf"},
+		{"plain protobuf", "application/protobuf", pod, "application/protobuf"},
+		{"protobuf of other types", "application/vnd.kubernetes.protobuf", greeting{}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			s, err := negotiation.Default.NegotiateOutputMediaType(req, c.obj)
+			if c.want == "" {
+				status, ok := err.(interface{ Status() metav1.Status })
+				if !ok || status.Status().Code != http.StatusNotAcceptable {
+					t.Fatalf("got %v, %v, want a NotAcceptable error", s, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.MediaType() != c.want {
+				t.Errorf("got %s, want %s", s.MediaType(), c.want)
+			}
+		})
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	clauses := negotiation.ParseAccept("text/plain; q=0.5, */json, application/*;charset=utf-8, invalid/;q=1, */*;q=x")
+	if len(clauses) != 3 {
+		t.Fatalf("got %d clauses, want 3: %+v", len(clauses), clauses)
+	}
+	if c := clauses[0]; c.Type != "text" || c.SubType != "plain" || c.Q != 0.5 {
+		t.Errorf("got %+v", c)
+	}
+	if c := clauses[1]; c.Type != "application" || c.SubType != "*" || c.Q != 1 || c.Params["charset"] != "utf-8" {
+		t.Errorf("got %+v", c)
+	}
+	if c := clauses[2]; c.Type != "*" || c.SubType != "*" || c.Q != 0 {
+		t.Errorf("got %+v", c)
+	}
+}
+
+// TestEncodeKubernetesProtobuf checks that objects written as Kubernetes protobuf are read by
+// the decoders of client-go.
+func TestEncodeKubernetesProtobuf(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/vnd.kubernetes.protobuf")
+	status := apierrors.NewNotFound(corev1.Resource("pods"), "web").Status()
+	for _, obj := range []interface{}{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web"}}, &status} {
+		s, data, err := negotiation.Default.Encode(req, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.ContentType() != "application/vnd.kubernetes.protobuf" {
+			t.Fatalf("got %s", s.ContentType())
+		}
+		decoded, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+		if err != nil {
+			t.Fatalf("%T: %v", obj, err)
+		}
+		switch d := decoded.(type) {
+		case *corev1.Pod:
+			if d.Name != "web" || gvk.Kind != "Pod" {
+				t.Errorf("got %s %s", gvk, d.Name)
+			}
+		case *metav1.Status:
+			if d.Code != http.StatusNotFound || d.Reason != metav1.StatusReasonNotFound {
+				t.Errorf("got %+v", d)
+			}
+		default:
+			t.Errorf("got %T", decoded)
+		}
+	}
+}
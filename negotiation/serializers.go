@@ -0,0 +1,199 @@
+package negotiation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// Serializer encodes objects into a single media type.
+type Serializer interface {
+	// MediaType returns the media type written by the Serializer, eg. application/json.
+	MediaType() string
+	// ContentType returns the value of the Content-Type header written with the response.
+	ContentType() string
+	// Accepts returns true if the Serializer is able to encode obj.
+	Accepts(obj interface{}) bool
+	Encode(w io.Writer, obj interface{}) error
+}
+
+var (
+	JSON     Serializer = jsonSerializer{}
+	YAML     Serializer = yamlSerializer{}
+	XML      Serializer = xmlSerializer{}
+	Text     Serializer = textSerializer{}
+	Protobuf Serializer = protobufSerializer{}
+
+	// KubernetesProtobuf writes the objects of the client-go scheme, including metav1.Status,
+	// in the protobuf envelope of the Kubernetes API, which client-go and kubectl decode.
+	KubernetesProtobuf Serializer = NewKubernetesProtobuf(scheme.Scheme)
+)
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) MediaType() string            { return "application/json" }
+func (jsonSerializer) ContentType() string          { return "application/json" }
+func (jsonSerializer) Accepts(obj interface{}) bool { return true }
+
+func (jsonSerializer) Encode(w io.Writer, obj interface{}) error {
+	return json.NewEncoder(w).Encode(obj)
+}
+
+type yamlSerializer struct{}
+
+func (yamlSerializer) MediaType() string            { return "application/yaml" }
+func (yamlSerializer) ContentType() string          { return "application/yaml" }
+func (yamlSerializer) Accepts(obj interface{}) bool { return true }
+
+func (yamlSerializer) Encode(w io.Writer, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+type xmlSerializer struct{}
+
+func (xmlSerializer) MediaType() string   { return "application/xml" }
+func (xmlSerializer) ContentType() string { return "application/xml" }
+
+// Accepts returns true for structs only. encoding/xml can't encode maps, and slices would be
+// written as a document with several root elements. Structs with fields it can't encode, eg.
+// maps, fail in Encode, so that Negotiator.Encode falls back to another Serializer.
+func (xmlSerializer) Accepts(obj interface{}) bool {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct
+}
+
+func (xmlSerializer) Encode(w io.Writer, obj interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(obj)
+}
+
+type textSerializer struct{}
+
+func (textSerializer) MediaType() string   { return "text/plain" }
+func (textSerializer) ContentType() string { return "text/plain; charset=utf-8" }
+
+// Accepts returns true for strings, scalars, errors, fmt.Stringers and metav1.Status.
+// A metav1.Status is written as its message.
+func (textSerializer) Accepts(obj interface{}) bool {
+	switch obj.(type) {
+	case string, []byte, error, fmt.Stringer, metav1.Status, *metav1.Status:
+		return true
+	}
+	switch reflect.ValueOf(obj).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (textSerializer) Encode(w io.Writer, obj interface{}) error {
+	var err error
+	switch t := obj.(type) {
+	case []byte:
+		_, err = w.Write(t)
+	case metav1.Status:
+		_, err = io.WriteString(w, t.Message)
+	case *metav1.Status:
+		_, err = io.WriteString(w, t.Message)
+	default:
+		_, err = fmt.Fprint(w, obj)
+	}
+	return err
+}
+
+// protoMarshaler is implemented by gogo and golang protobuf messages, including the k8s api types.
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type protobufSerializer struct{}
+
+func (protobufSerializer) MediaType() string   { return "application/protobuf" }
+func (protobufSerializer) ContentType() string { return "application/protobuf" }
+
+func (protobufSerializer) Accepts(obj interface{}) bool {
+	_, ok := obj.(protoMarshaler)
+	return ok
+}
+
+func (protobufSerializer) Encode(w io.Writer, obj interface{}) error {
+	m, ok := obj.(protoMarshaler)
+	if !ok {
+		return fmt.Errorf("%T is not a protobuf message", obj)
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+type kubernetesProtobufSerializer struct {
+	scheme *runtime.Scheme
+	proto  *protobuf.Serializer
+}
+
+// NewKubernetesProtobuf returns a Serializer of application/vnd.kubernetes.protobuf for the
+// types of s. Objects are written with the kind they are registered with in s.
+func NewKubernetesProtobuf(s *runtime.Scheme) Serializer {
+	return kubernetesProtobufSerializer{scheme: s, proto: protobuf.NewSerializer(s, s)}
+}
+
+func (kubernetesProtobufSerializer) MediaType() string {
+	return "application/vnd.kubernetes.protobuf"
+}
+
+func (kubernetesProtobufSerializer) ContentType() string {
+	return "application/vnd.kubernetes.protobuf"
+}
+
+func (s kubernetesProtobufSerializer) Accepts(obj interface{}) bool {
+	o, ok := obj.(runtime.Object)
+	if !ok {
+		return false
+	}
+	if _, ok := obj.(protoMarshaler); !ok {
+		return false
+	}
+	_, _, err := s.scheme.ObjectKinds(o)
+	return err == nil
+}
+
+func (s kubernetesProtobufSerializer) Encode(w io.Writer, obj interface{}) error {
+	o, ok := obj.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a Kubernetes object", obj)
+	}
+	// objects returned by typed clients have no kind, the envelope needs it
+	if o.GetObjectKind().GroupVersionKind().Empty() {
+		gvks, _, err := s.scheme.ObjectKinds(o)
+		if err != nil {
+			return err
+		}
+		o = o.DeepCopyObject()
+		o.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+	return s.proto.Encode(o, w)
+}
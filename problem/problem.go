@@ -0,0 +1,190 @@
+// Package problem converts metav1.Status into RFC 7807 problem details and back, so that the
+// same error can be written to k8s clients as metav1.Status and to other clients as
+// application/problem+json.
+//
+// The mapping is lossless in both directions:
+//
+//	metav1.Status                 Problem
+//	reason                        type (TypeURI), title
+//	code, status                  status
+//	message                       detail
+//	metadata.selfLink             instance
+//	details.causes                invalid-params
+//	details.{kind,group,name,uid,retryAfterSeconds} and the rest of metadata are extension members
+//
+// The title is derived from the type, as RFC 7807 asks titles not to change between occurrences
+// of a problem type. The status field of metav1.Status is derived from the code, Failure for
+// codes of 400 and above and Success otherwise, unless the extension member result says otherwise.
+// Statuses are normalized like ErrorToAPIStatus of package responsewriters does, ie. their kind
+// is Status and their status field is set.
+package problem
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MediaType is the media type of problem details in JSON.
+const MediaType = "application/problem+json"
+
+// TypeBaseURI is the prefix of the type URIs of status reasons, eg. the type of the reason
+// NotFound is urn:k8s:status-reason:NotFound. Problems of an empty reason have the type
+// about:blank, as defined by RFC 7807.
+var TypeBaseURI = "urn:k8s:status-reason:"
+
+// Problem is an RFC 7807 problem details object with the extension members of metav1.Status.
+type Problem struct {
+	// Type is a URI reference that identifies the problem type, see TypeBaseURI.
+	Type string `json:"type,omitempty"`
+	// Title is a short summary of the problem type, eg. Not Found.
+	Title string `json:"title,omitempty"`
+	// Status is the HTTP status code.
+	Status int32 `json:"status,omitempty"`
+	// Detail explains this occurrence of the problem, it is the message of the metav1.Status.
+	Detail string `json:"detail,omitempty"`
+	// Instance identifies this occurrence of the problem, eg. the ID of the request.
+	Instance string `json:"instance,omitempty"`
+
+	// InvalidParams are the causes of the problem.
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+
+	// extension members holding the rest of metav1.Status
+	Kind               string    `json:"kind,omitempty"`
+	Group              string    `json:"group,omitempty"`
+	Name               string    `json:"name,omitempty"`
+	UID                types.UID `json:"uid,omitempty"`
+	RetryAfterSeconds  int32     `json:"retryAfterSeconds,omitempty"`
+	ResourceVersion    string    `json:"resourceVersion,omitempty"`
+	Continue           string    `json:"continue,omitempty"`
+	RemainingItemCount *int64    `json:"remainingItemCount,omitempty"`
+	// Result is the status field of a metav1.Status that doesn't match its code, eg. Success
+	// for a 404. It is empty otherwise.
+	Result string `json:"result,omitempty"`
+	// EmptyDetails is true if the metav1.Status has empty details.
+	EmptyDetails bool `json:"emptyDetails,omitempty"`
+}
+
+// InvalidParam is a cause of a problem, in the format of the example of RFC 7807.
+type InvalidParam struct {
+	// Name is the field of the cause, eg. addresses[0].city. It is empty if the cause
+	// is not about a field, eg. a malformed body.
+	Name string `json:"name,omitempty"`
+	// Reason is the message of the cause.
+	Reason string `json:"reason,omitempty"`
+	// Type is the metav1.CauseType of the cause, eg. FieldValueRequired.
+	Type metav1.CauseType `json:"type,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// FromStatus returns the problem details of status.
+func FromStatus(status *metav1.Status) *Problem {
+	p := &Problem{
+		Type:               TypeURI(status.Reason),
+		Status:             status.Code,
+		Detail:             status.Message,
+		Instance:           status.SelfLink,
+		ResourceVersion:    status.ResourceVersion,
+		Continue:           status.Continue,
+		RemainingItemCount: status.RemainingItemCount,
+	}
+	p.Title = title(status.Reason, status.Code)
+	if status.Status != "" && status.Status != resultOf(status.Code) {
+		p.Result = status.Status
+	}
+
+	if d := status.Details; d != nil {
+		p.EmptyDetails = d.Name == "" && d.Group == "" && d.Kind == "" && d.UID == "" && len(d.Causes) == 0 && d.RetryAfterSeconds == 0
+		p.Kind, p.Group, p.Name, p.UID, p.RetryAfterSeconds = d.Kind, d.Group, d.Name, d.UID, d.RetryAfterSeconds
+		for _, c := range d.Causes {
+			p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: c.Field, Reason: c.Message, Type: c.Type})
+		}
+	}
+	return p
+}
+
+// ToStatus returns the metav1.Status of p. Problems of other sources than FromStatus are
+// supported as well: a type that doesn't start with TypeBaseURI is used as the reason as is,
+// so that FromStatus returns it again. Their title is not kept.
+func ToStatus(p *Problem) *metav1.Status {
+	status := &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{
+			SelfLink:           p.Instance,
+			ResourceVersion:    p.ResourceVersion,
+			Continue:           p.Continue,
+			RemainingItemCount: p.RemainingItemCount,
+		},
+		Status:  resultOf(p.Status),
+		Message: p.Detail,
+		Reason:  Reason(p.Type),
+		Code:    p.Status,
+	}
+	if p.Result != "" {
+		status.Status = p.Result
+	}
+
+	if p.EmptyDetails || p.Kind != "" || p.Group != "" || p.Name != "" || p.UID != "" || p.RetryAfterSeconds != 0 || len(p.InvalidParams) > 0 {
+		d := &metav1.StatusDetails{Kind: p.Kind, Group: p.Group, Name: p.Name, UID: p.UID, RetryAfterSeconds: p.RetryAfterSeconds}
+		for _, ip := range p.InvalidParams {
+			d.Causes = append(d.Causes, metav1.StatusCause{Field: ip.Name, Message: ip.Reason, Type: ip.Type})
+		}
+		status.Details = d
+	}
+	return status
+}
+
+// TypeURI returns the type URI of reason.
+func TypeURI(reason metav1.StatusReason) string {
+	switch {
+	case reason == "":
+		return "about:blank"
+	case strings.Contains(string(reason), ":"):
+		return string(reason) // the type of a foreign problem, see ToStatus
+	}
+	return TypeBaseURI + string(reason)
+}
+
+// Reason returns the status reason of the type URI typ.
+func Reason(typ string) metav1.StatusReason {
+	switch {
+	case typ == "" || typ == "about:blank":
+		return ""
+	case strings.HasPrefix(typ, TypeBaseURI):
+		return metav1.StatusReason(strings.TrimPrefix(typ, TypeBaseURI))
+	}
+	return metav1.StatusReason(typ)
+}
+
+// resultOf returns the status field of a metav1.Status with code.
+func resultOf(code int32) string {
+	if code >= http.StatusBadRequest || code == 0 {
+		return metav1.StatusFailure
+	}
+	return metav1.StatusSuccess
+}
+
+// title returns the title of reason, eg. Not Found for NotFound. The title of problems without
+// reason is the status text of code.
+func title(reason metav1.StatusReason, code int32) string {
+	if reason == "" || strings.Contains(string(reason), ":") {
+		return http.StatusText(int(code))
+	}
+	var b strings.Builder
+	for i, r := range string(reason) {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,10 +1,9 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
+	"github.com/tamalsaha/learn-chi/binding"
 	"github.com/unrolled/render"
-	"go.wandrs.dev/binding"
 	httpw "go.wandrs.dev/http"
 	"go.wandrs.dev/inject"
 	"io"
@@ -118,22 +117,37 @@ var (
 )
 
 func main() {
-	// fn := h_returns_custom_error_interface
-	fn := h_returns_string_array
-	data, _ := json.Marshal(fn())
-	fmt.Println(string(data))
-
-	w := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	handlers := []interface{}{
+		h_no_return,
+		h_returns_error,
+		h_returns_custom_error,
+		h_returns_custom_error_interface,
+		h_returns_string,
+		h_returns_int,
+		h_returns_bool,
+		h_returns_byte_array,
+		h_returns_string_array,
+		h_returns_int_array,
+		h_returns_bool_array,
+		h_returns_struct,
+		h_returns_slice,
+		h_returns_struct_err,
+		h_returns_too_many_returns,
+	}
+	for _, fn := range handlers {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
 
-	binding.Injector(render.New())(binding.HandlerFunc(fn)).ServeHTTP(w, req)
+		binding.Injector(render.New())(binding.Handler(fn)).ServeHTTP(w, req)
 
-	resp := w.Result()
-	body, _ := io.ReadAll(resp.Body)
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
 
-	fmt.Println(resp.StatusCode)
-	fmt.Println(resp.Header.Get("Content-Type"))
-	fmt.Println(string(body))
+		fmt.Println(reflect.TypeOf(fn).String())
+		fmt.Println(resp.StatusCode)
+		fmt.Println(resp.Header.Get("Content-Type"))
+		fmt.Println(string(body))
+	}
 }
 
 func main__() {
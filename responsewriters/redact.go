@@ -0,0 +1,109 @@
+package responsewriters
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/i18n"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// ProductionMode makes ErrorNegotiated hide the messages of errors that may expose internals,
+// eg. file paths or SQL, from clients: errors without a status, errors matched by the registry
+// whose message defaults to the one of the error, and internal errors. Their message is replaced
+// with the status text of their code and the request ID set by chim.RequestID, eg.
+// Internal Server Error (request ID 01FX...), and the full error is logged with its stack trace,
+// see WithStack, through the logger of the request set by chim.NewLogr, so that it can be found
+// by the ID. The messages of errors of
+// types allowed by AllowErrorMessages are kept. It is read without synchronization, so it must
+// be set before requests are served.
+var ProductionMode bool
+
+var safeErrorTypes = struct {
+	sync.RWMutex
+	types map[reflect.Type]bool
+}{types: map[reflect.Type]bool{}}
+
+// AllowErrorMessages allows the messages of errors of the types of errs to be written in
+// ProductionMode, eg.
+//
+//	responsewriters.AllowErrorMessages(&QuotaError{}, ErrMaintenance)
+//
+// If an error is redacted, the message of the first error of an allowed type in its unwrap
+// chain is written instead. Error types may be allowed while requests are served.
+func AllowErrorMessages(errs ...error) {
+	safeErrorTypes.Lock()
+	defer safeErrorTypes.Unlock()
+	for _, err := range errs {
+		safeErrorTypes.types[reflect.TypeOf(err)] = true
+	}
+}
+
+// safeMessage returns the message of the first error of an allowed type in the unwrap chain of err.
+func safeMessage(err error) (msg string, ok bool) {
+	safeErrorTypes.RLock()
+	defer safeErrorTypes.RUnlock()
+	walkErrors(err, func(e error) bool {
+		if safeErrorTypes.types[reflect.TypeOf(e)] {
+			msg, ok = e.Error(), true
+		}
+		return ok
+	})
+	return msg, ok
+}
+
+// mustRedact returns true if the message of status may expose internals in ProductionMode.
+func mustRedact(status *metav1.Status, src statusSource) bool {
+	return src == sourceNone || src == sourceMatcherDetail || status.Reason == metav1.StatusReasonInternalError
+}
+
+// redactStatus returns status with the message of err hidden, see ProductionMode.
+func redactStatus(err error, status *metav1.Status, req *http.Request, trans ut.Translator) *metav1.Status {
+	if d := status.Details; d != nil {
+		d.Causes = nil // causes of internal errors repeat the message
+	}
+	if msg, ok := safeMessage(err); ok {
+		status.Message = msg
+		return status
+	}
+
+	status.Message = ""
+	status = localizeStatus(status, trans)
+	if status.Message == "" {
+		status.Message = http.StatusText(int(status.Code))
+	}
+	if reqID := chim.GetReqID(req.Context()); reqID != "" {
+		if msg, ok := i18n.T(trans, i18n.KeyRedacted, status.Message, reqID); ok {
+			status.Message = msg
+		} else {
+			status.Message = fmt.Sprintf("%s (request ID %s)", status.Message, reqID)
+		}
+	}
+	return status
+}
+
+// logError logs err, whose message is redacted, with the logger of req and the stack trace
+// recorded by WithStack, if any.
+func logError(req *http.Request, err error, status *metav1.Status) {
+	stack, _ := StackOf(err)
+	log, ok := chim.LookupLogEntry(req)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("request %s failed: %+v\n%s", chim.GetReqID(req.Context()), err, stack))
+		return
+	}
+	kvs := []interface{}{
+		"resp_status", status.Code,
+		"status_reason", status.Reason,
+		"error_type", fmt.Sprintf("%T", unwrapStack(err)),
+		"error_verbose", fmt.Sprintf("%+v", err),
+	}
+	if stack != "" {
+		kvs = append(kvs, "stack", stack)
+	}
+	log.Error(err, "request failed", kvs...)
+}
@@ -0,0 +1,198 @@
+package responsewriters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// StatusFunc returns the status of an error matched by a matcher registered with RegisterErrorIs,
+// RegisterErrorAs or RegisterErrorFunc. err is the matched error of the unwrap chain. The message
+// of the status defaults to the message of the error returned by the handler, which includes the
+// context added by wrapping errors.
+type StatusFunc func(err error) metav1.Status
+
+// StatusOf returns a StatusFunc of failures with code and reason, eg.
+//
+//	responsewriters.RegisterErrorIs(ErrQuotaExceeded, responsewriters.StatusOf(http.StatusTooManyRequests, metav1.StatusReasonTooManyRequests))
+func StatusOf(code int32, reason metav1.StatusReason) StatusFunc {
+	return func(err error) metav1.Status {
+		return metav1.Status{Status: metav1.StatusFailure, Code: code, Reason: reason}
+	}
+}
+
+// errorMatcher is an error matcher of the registry.
+type errorMatcher struct {
+	match func(err error) (error, bool) // returns the error passed to fn
+	fn    StatusFunc
+}
+
+var errorMatchers = struct {
+	sync.RWMutex
+	matchers []errorMatcher
+}{}
+
+func registerErrorMatcher(m errorMatcher) {
+	errorMatchers.Lock()
+	defer errorMatchers.Unlock()
+	errorMatchers.matchers = append(errorMatchers.matchers, m)
+}
+
+// RegisterErrorIs registers fn as the status of errors that are target, like errors.Is,
+// eg. sentinel errors like sql.ErrNoRows.
+func RegisterErrorIs(target error, fn StatusFunc) {
+	comparable := reflect.TypeOf(target).Comparable()
+	registerErrorMatcher(errorMatcher{
+		match: func(err error) (error, bool) {
+			if comparable && err == target {
+				return err, true
+			}
+			if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+				return err, true
+			}
+			return nil, false
+		},
+		fn: fn,
+	})
+}
+
+// RegisterErrorAs registers fn as the status of errors that can be assigned to the variable
+// target points to, like errors.As, eg.
+//
+//	var pathErr *fs.PathError
+//	responsewriters.RegisterErrorAs(&pathErr, fn)
+//
+// fn is called with the error as the type of target, so it may type assert it.
+func RegisterErrorAs(target interface{}, fn StatusFunc) {
+	typ := reflect.TypeOf(target)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface && !typ.Elem().Implements(errorType) {
+		panic(fmt.Sprintf("responsewriters: target must be a pointer to an interface or to a type implementing error, found %T", target))
+	}
+	targetType := typ.Elem()
+	registerErrorMatcher(errorMatcher{
+		match: func(err error) (error, bool) {
+			if reflect.TypeOf(err).AssignableTo(targetType) {
+				return err, true
+			}
+			if x, ok := err.(interface{ As(interface{}) bool }); ok {
+				v := reflect.New(targetType)
+				if x.As(v.Interface()) {
+					if e, ok := v.Elem().Interface().(error); ok {
+						return e, true
+					}
+					return err, true
+				}
+			}
+			return nil, false
+		},
+		fn: fn,
+	})
+}
+
+// RegisterErrorFunc registers fn as the status of errors match returns true for,
+// eg. RegisterErrorFunc(storage.IsConflict, StatusOf(http.StatusConflict, metav1.StatusReasonConflict)).
+func RegisterErrorFunc(match func(err error) bool, fn StatusFunc) {
+	registerErrorMatcher(errorMatcher{
+		match: func(err error) (error, bool) {
+			return err, match(err)
+		},
+		fn: fn,
+	})
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// maxErrorDepth limits the errors visited by walkErrors, in case of cyclic error chains.
+const maxErrorDepth = 100
+
+// statusSource tells where the status of an error comes from.
+type statusSource int
+
+const (
+	sourceNone          statusSource = iota // the error has no status
+	sourceStatus                            // a Status method
+	sourceMatcher                           // a registered matcher
+	sourceMatcherDetail                     // a registered matcher that defaulted the message to the one of the error
+)
+
+// LookupStatus returns the status of err, if err or an error of its unwrap chain has a Status
+// method, like apierrors.StatusError, or is matched by a registered matcher. Errors are visited
+// depth first, starting with err, following both Unwrap() error and Unwrap() []error, as well
+// as Errors() []error of k8s aggregates. The first match wins. At every error, a Status method
+// takes precedence over the matchers, which are tried in the order they are registered.
+func LookupStatus(err error) (metav1.Status, bool) {
+	status, src := lookupStatus(err)
+	return status, src != sourceNone
+}
+
+func lookupStatus(err error) (status metav1.Status, src statusSource) {
+	errorMatchers.RLock()
+	matchers := errorMatchers.matchers
+	errorMatchers.RUnlock()
+
+	walkErrors(err, func(e error) bool {
+		if se, ok := e.(statusError); ok {
+			status, src = se.Status(), sourceStatus
+			return true
+		}
+		for _, m := range matchers {
+			if matched, ok := m.match(e); ok {
+				status, src = m.fn(matched), sourceMatcher
+				if status.Message == "" {
+					status.Message, src = err.Error(), sourceMatcherDetail
+				}
+				return true
+			}
+		}
+		return false
+	})
+	return status, src
+}
+
+// walkErrors calls fn for err and the errors of its unwrap chain, depth first, until fn returns true.
+func walkErrors(err error, fn func(e error) bool) {
+	visited := 0
+	var walk func(e error) bool
+	walk = func(e error) bool {
+		if e == nil || visited >= maxErrorDepth {
+			return false
+		}
+		visited++
+		if fn(e) {
+			return true
+		}
+
+		var children []error
+		switch x := e.(type) {
+		case interface{ Unwrap() error }:
+			children = []error{x.Unwrap()}
+		case interface{ Unwrap() []error }:
+			children = x.Unwrap()
+		case interface{ Errors() []error }:
+			children = x.Errors()
+		}
+		for _, child := range children {
+			if walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(err)
+}
+
+func init() {
+	RegisterErrorFunc(storage.IsConflict, StatusOf(http.StatusConflict, metav1.StatusReasonConflict))
+	RegisterErrorIs(fs.ErrNotExist, StatusOf(http.StatusNotFound, metav1.StatusReasonNotFound))
+	RegisterErrorIs(fs.ErrExist, StatusOf(http.StatusConflict, metav1.StatusReasonAlreadyExists))
+	RegisterErrorIs(fs.ErrPermission, StatusOf(http.StatusForbidden, metav1.StatusReasonForbidden))
+	RegisterErrorIs(sql.ErrNoRows, StatusOf(http.StatusNotFound, metav1.StatusReasonNotFound))
+	RegisterErrorIs(context.DeadlineExceeded, StatusOf(http.StatusGatewayTimeout, metav1.StatusReasonTimeout))
+}
@@ -0,0 +1,67 @@
+package responsewriters
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// stackError is an error with the stack trace of the goroutine that created or received it.
+type stackError struct {
+	err   error
+	stack []byte
+}
+
+// WithStack returns err with the stack trace of the calling goroutine, which is logged with
+// errors whose message is redacted in ProductionMode and shown on development error pages. It
+// returns err as is if it is nil or already has a stack trace. binding records the stack of the
+// errors returned by handlers and providers, call it where an error is created to point at its
+// origin instead, eg.
+//
+//	if err := rows.Scan(&u); err != nil {
+//		return nil, responsewriters.WithStack(err)
+//	}
+func WithStack(err error) error {
+	if _, ok := StackOf(err); ok || err == nil {
+		return err
+	}
+	return &stackError{err: err, stack: debug.Stack()}
+}
+
+// StackOf returns the stack trace recorded by WithStack in the unwrap chain of err.
+func StackOf(err error) (stack string, ok bool) {
+	walkErrors(err, func(e error) bool {
+		if se, isStackErr := e.(*stackError); isStackErr {
+			stack, ok = string(se.stack), true
+		}
+		return ok
+	})
+	return stack, ok
+}
+
+// unwrapStack returns the error wrapped by WithStack, or err if it has no stack trace of its own.
+func unwrapStack(err error) error {
+	if se, ok := err.(*stackError); ok {
+		return se.err
+	}
+	return err
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// Format formats the error like the error it wraps, so that %+v keeps its details.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%+v", e.err)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.err.Error())
+	default:
+		fmt.Fprint(s, e.err.Error())
+	}
+}
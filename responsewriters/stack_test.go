@@ -0,0 +1,90 @@
+package responsewriters_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/negotiation"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+)
+
+// recordingLogger records the key/value pairs of the errors it logs.
+type recordingLogger struct {
+	kvs    []interface{}
+	errors *[]map[string]interface{}
+}
+
+func (l recordingLogger) Enabled() bool                       { return true }
+func (l recordingLogger) Info(msg string, kvs ...interface{}) {}
+func (l recordingLogger) V(level int) logr.Logger             { return l }
+func (l recordingLogger) WithName(name string) logr.Logger    { return l }
+func (l recordingLogger) WithValues(kvs ...interface{}) logr.Logger {
+	return recordingLogger{kvs: append(append([]interface{}{}, l.kvs...), kvs...), errors: l.errors}
+}
+
+func (l recordingLogger) Error(err error, msg string, kvs ...interface{}) {
+	m := map[string]interface{}{"msg": msg}
+	all := append(append([]interface{}{}, l.kvs...), kvs...)
+	for i := 0; i+1 < len(all); i += 2 {
+		m[fmt.Sprint(all[i])] = all[i+1]
+	}
+	*l.errors = append(*l.errors, m)
+}
+
+//go:noinline
+func failingQuery() error {
+	return responsewriters.WithStack(errors.New("pq: relation \"users\" does not exist"))
+}
+
+func TestWithStack(t *testing.T) {
+	err := failingQuery()
+	stack, ok := responsewriters.StackOf(fmt.Errorf("list users: %w", err))
+	if !ok || !strings.Contains(stack, "failingQuery") {
+		t.Errorf("got stack %q, want the stack of failingQuery", stack)
+	}
+	if again := responsewriters.WithStack(err); again != err {
+		t.Errorf("WithStack wrapped an error with a stack again")
+	}
+	if got := fmt.Sprintf("%v %q", err, err); got != `pq: relation "users" does not exist "pq: relation \"users\" does not exist"` {
+		t.Errorf("got %s", got)
+	}
+	if _, ok := responsewriters.StackOf(errors.New("plain")); ok {
+		t.Errorf("plain error has a stack")
+	}
+}
+
+// TestLogErrorStack checks that redacted errors are logged with their stack by the logger of
+// the request.
+func TestLogErrorStack(t *testing.T) {
+	defer func(production bool) { responsewriters.ProductionMode = production }(responsewriters.ProductionMode)
+	responsewriters.ProductionMode = true
+
+	var logged []map[string]interface{}
+	handler := chim.RequestID(chim.NewLogr(recordingLogger{errors: &logged}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responsewriters.ErrorNegotiated(failingQuery(), negotiation.Default, w, r)
+	})))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "relation") {
+		t.Errorf("got %d %s, want a redacted 500", w.Code, w.Body)
+	}
+	if len(logged) != 1 {
+		t.Fatalf("got %d logged errors, want 1", len(logged))
+	}
+	if stack, _ := logged[0]["stack"].(string); !strings.Contains(stack, "failingQuery") {
+		t.Errorf("got stack %q, want the stack of failingQuery", stack)
+	}
+	if reqID, _ := logged[0]["req_id"].(string); reqID == "" {
+		t.Errorf("the request ID is not logged")
+	}
+	if errType := logged[0]["error_type"]; errType != "*errors.errorString" {
+		t.Errorf("got error type %v, want *errors.errorString", errType)
+	}
+}
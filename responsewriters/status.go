@@ -0,0 +1,163 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package responsewriters
+
+import (
+	"fmt"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/tamalsaha/learn-chi/i18n"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// statusError is an object that can be converted into an metav1.Status
+type statusError interface {
+	Status() metav1.Status
+}
+
+// ErrorToAPIStatus converts an error to an metav1.Status object. Errors are looked up with
+// LookupStatus, so wrapped API errors and errors matched by the registry keep their status.
+// Other errors result in 500 Internal Server Error.
+func ErrorToAPIStatus(err error) *metav1.Status {
+	status, src := errorToAPIStatus(err)
+	if src == sourceNone && err != nil {
+		handleUnknownError(err)
+	}
+	return status
+}
+
+// handleUnknownError logs an error that has no status.
+func handleUnknownError(err error) {
+	// Log errors that were not converted to an error status
+	// by REST storage - these typically indicate programmer
+	// error by not using pkg/api/errors, or unexpected failure
+	// cases.
+	runtime.HandleError(fmt.Errorf("apiserver received an error that is not an metav1.Status: %#+v: %v", err, err))
+}
+
+// errorToAPIStatus is ErrorToAPIStatus without logging errors that have no status.
+func errorToAPIStatus(err error) (*metav1.Status, statusSource) {
+	if err == nil {
+		return &metav1.Status{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Status",
+				APIVersion: "v1",
+			},
+			Status: metav1.StatusSuccess,
+			Code:   http.StatusOK,
+		}, sourceStatus
+	}
+
+	if status, src := lookupStatus(err); src != sourceNone {
+		if len(status.Status) == 0 {
+			status.Status = metav1.StatusFailure
+		}
+		switch status.Status {
+		case metav1.StatusSuccess:
+			if status.Code == 0 {
+				status.Code = http.StatusOK
+			}
+		case metav1.StatusFailure:
+			if status.Code == 0 {
+				status.Code = http.StatusInternalServerError
+			}
+		default:
+			runtime.HandleError(fmt.Errorf("apiserver received an error with wrong status field : %#+v", err))
+			if status.Code == 0 {
+				status.Code = http.StatusInternalServerError
+			}
+		}
+		status.Kind = "Status"
+		status.APIVersion = "v1"
+		//TODO: check for invalid responses
+		return &status, src
+	}
+
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusInternalServerError,
+		Reason:  metav1.StatusReasonUnknown,
+		Message: err.Error(),
+	}, sourceNone
+}
+
+// LocalizedErrorToAPIStatus is ErrorToAPIStatus with the message translated by trans, eg. the
+// translator returned by i18n.FromRequest. Messages are looked up by the reason of the status
+// using i18n.StatusKey and i18n.StatusNamedKey. Only messages that say no more than the reason,
+// eg. an empty message or the message of apierrors.NewNotFound, are translated. Other messages
+// and messages missing from the catalog are kept as is.
+func LocalizedErrorToAPIStatus(err error, trans ut.Translator) *metav1.Status {
+	return localizeStatus(ErrorToAPIStatus(err), trans)
+}
+
+// reasonMessages return the messages apierrors creates for a reason, kind is the qualified
+// kind and name the name of the details of the status.
+var reasonMessages = map[metav1.StatusReason]func(kind, name string) string{
+	metav1.StatusReasonNotFound: func(kind, name string) string {
+		return fmt.Sprintf("%s %q not found", kind, name)
+	},
+	metav1.StatusReasonAlreadyExists: func(kind, name string) string {
+		return fmt.Sprintf("%s %q already exists", kind, name)
+	},
+}
+
+// isReasonMessage returns true if the message of status is empty or the default text of its
+// reason, which the catalog may replace.
+func isReasonMessage(status *metav1.Status, kind, name string) bool {
+	switch status.Message {
+	case "", string(status.Reason), http.StatusText(int(status.Code)):
+		return true
+	}
+	if msg, ok := reasonMessages[status.Reason]; ok {
+		return status.Message == msg(kind, name)
+	}
+	return false
+}
+
+// localizeStatus translates the message of status, see LocalizedErrorToAPIStatus.
+func localizeStatus(status *metav1.Status, trans ut.Translator) *metav1.Status {
+	if trans == nil || status.Status != metav1.StatusFailure || status.Reason == "" {
+		return status
+	}
+
+	var kind, name string
+	if d := status.Details; d != nil {
+		kind, name = d.Kind, d.Name
+		if d.Group != "" {
+			kind += "." + d.Group
+		}
+	}
+	if !isReasonMessage(status, kind, name) {
+		return status
+	}
+	if name != "" {
+		if msg, ok := i18n.T(trans, i18n.StatusNamedKey(string(status.Reason)), kind, name); ok {
+			status.Message = msg
+			return status
+		}
+	}
+	if msg, ok := i18n.T(trans, i18n.StatusKey(string(status.Reason)), kind); ok {
+		status.Message = msg
+	}
+	return status
+}
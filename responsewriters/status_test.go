@@ -0,0 +1,54 @@
+package responsewriters_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"testing"
+
+	"github.com/tamalsaha/learn-chi/i18n"
+	"github.com/tamalsaha/learn-chi/responsewriters"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestLocalizedErrorToAPIStatus checks that only generic messages are replaced by the message
+// of the catalog.
+func TestLocalizedErrorToAPIStatus(t *testing.T) {
+	specific := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusNotFound,
+		Reason:  metav1.StatusReasonNotFound,
+		Message: "the namespace of the pod was deleted",
+		Details: &metav1.StatusDetails{Kind: "pods", Name: "web"},
+	}}
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"NotFound", apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web"), `deployments.apps "web" wurde nicht gefunden`},
+		{"wrapped NotFound", fmt.Errorf("get web: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")), `pods "web" wurde nicht gefunden`},
+		{"AlreadyExists", apierrors.NewAlreadyExists(schema.GroupResource{Resource: "pods"}, "web"), `pods "web" existiert bereits`},
+		{"status text", &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusServiceUnavailable,
+			Reason:  metav1.StatusReasonServiceUnavailable,
+			Message: http.StatusText(http.StatusServiceUnavailable),
+		}}, "Der Dienst ist vorübergehend nicht verfügbar"},
+		{"specific message", specific, "the namespace of the pod was deleted"},
+		{"registry", fmt.Errorf("open config.yaml: %w", fs.ErrNotExist), "open config.yaml: file does not exist"},
+		{"missing from the catalog", apierrors.NewBadRequest("name is required"), "name is required"},
+		{"unknown error", errors.New("boom"), "boom"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status := responsewriters.LocalizedErrorToAPIStatus(c.err, i18n.Default.Translator("de"))
+			if status.Message != c.want {
+				t.Errorf("got message %q, want %q", status.Message, c.want)
+			}
+		})
+	}
+}
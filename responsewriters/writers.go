@@ -0,0 +1,137 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package responsewriters
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/tamalsaha/learn-chi/chim"
+	"github.com/tamalsaha/learn-chi/i18n"
+	"github.com/tamalsaha/learn-chi/negotiation"
+	"github.com/tamalsaha/learn-chi/problem"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WriteObjectNegotiated renders an object in the content type negotiated by the client.
+func WriteObjectNegotiated(s *negotiation.Negotiator, statusCode int, object interface{}, w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
+	serializer, data, err := s.Encode(req, object)
+	if err != nil {
+		// if original statusCode was not successful we need to return the original error
+		// we cannot hide it behind negotiation problems
+		if statusCode < http.StatusOK || statusCode >= http.StatusBadRequest {
+			WriteRawJSON(statusCode, object, w)
+			return
+		}
+		status := ErrorToAPIStatus(err)
+		WriteRawJSON(int(status.Code), status, w)
+		return
+	}
+	w.Header().Set("Content-Type", serializer.ContentType())
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
+
+// ErrorNegotiated renders an error to the response, as metav1.Status in the content type negotiated
+// by the client or as RFC 7807 problem details if the client prefers application/problem+json. The
+// instance of problems is the request ID set by chim.RequestID. The message is translated into the
+// language of the Accept-Language header of req. In ProductionMode, messages that may expose
+// internals are replaced, see ProductionMode. Returns the HTTP status code of the error.
+func ErrorNegotiated(err error, s *negotiation.Negotiator, w http.ResponseWriter, req *http.Request) int {
+	return StatusNegotiated(ErrorStatus(err, req), s, w, req)
+}
+
+// StatusNegotiated writes status like ErrorNegotiated writes the status of an error, for callers
+// that got it from ErrorStatus. Returns the HTTP status code of status.
+func StatusNegotiated(status *metav1.Status, s *negotiation.Negotiator, w http.ResponseWriter, req *http.Request) int {
+	code := int(status.Code)
+	// when writing an error, check to see if the status indicates a retry after period
+	if status.Details != nil && status.Details.RetryAfterSeconds > 0 {
+		delay := strconv.Itoa(int(status.Details.RetryAfterSeconds))
+		w.Header().Set("Retry-After", delay)
+	}
+
+	if code == http.StatusNoContent {
+		w.WriteHeader(code)
+		return code
+	}
+
+	if prefersProblem(s, req, status) {
+		w.Header().Add("Vary", "Accept")
+		p := problem.FromStatus(status)
+		if p.Instance == "" {
+			p.Instance = chim.GetReqID(req.Context())
+		}
+		var buf bytes.Buffer
+		if err := negotiation.JSON.Encode(&buf, p); err != nil {
+			WriteRawJSON(code, status, w)
+			return code
+		}
+		w.Header().Set("Content-Type", problem.MediaType)
+		w.WriteHeader(code)
+		_, _ = w.Write(buf.Bytes())
+		return code
+	}
+	WriteObjectNegotiated(s, code, status, w, req)
+	return code
+}
+
+// ErrorStatus returns the status ErrorNegotiated writes for err in response to req, with its
+// message translated and, in ProductionMode, redacted. Errors without a status are logged.
+func ErrorStatus(err error, req *http.Request) *metav1.Status {
+	trans := i18n.FromRequest(req)
+	status, src := errorToAPIStatus(err)
+	if ProductionMode && mustRedact(status, src) {
+		logError(req, err, status)
+		return redactStatus(err, status, req, trans)
+	}
+	if src == sourceNone {
+		handleUnknownError(err)
+	}
+	return localizeStatus(status, trans)
+}
+
+// problemSerializer stands for problem.MediaType in the negotiation of errors.
+type problemSerializer struct {
+	negotiation.Serializer
+}
+
+func (problemSerializer) MediaType() string { return problem.MediaType }
+
+// prefersProblem returns true if the client prefers application/problem+json over the
+// media types of s. Clients that accept any media type get the ones of s.
+func prefersProblem(s *negotiation.Negotiator, req *http.Request, status *metav1.Status) bool {
+	serializers := append(append([]negotiation.Serializer{}, s.Serializers()...), problemSerializer{negotiation.JSON})
+	serializer, err := negotiation.New(serializers...).NegotiateOutputMediaType(req, status)
+	return err == nil && serializer.MediaType() == problem.MediaType
+}
+
+// WriteRawJSON writes a non-API object in JSON.
+func WriteRawJSON(statusCode int, object interface{}, w http.ResponseWriter) {
+	output, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(output)
+}
@@ -1,55 +0,0 @@
-/*
-Copyright 2016 The Kubernetes Authors.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package main
-
-import (
-	"encoding/json"
-	"net/http"
-	"strconv"
-)
-
-// ErrorNegotiated renders an error to the response. Returns the HTTP status code of the error.
-// The context is optional and may be nil.
-func ErrorNegotiated(err error, w http.ResponseWriter) int {
-	status := ErrorToAPIStatus(err)
-	code := int(status.Code)
-	// when writing an error, check to see if the status indicates a retry after period
-	if status.Details != nil && status.Details.RetryAfterSeconds > 0 {
-		delay := strconv.Itoa(int(status.Details.RetryAfterSeconds))
-		w.Header().Set("Retry-After", delay)
-	}
-
-	if code == http.StatusNoContent {
-		w.WriteHeader(code)
-		return code
-	}
-
-	WriteRawJSON(code, status, w)
-	return code
-}
-
-// WriteRawJSON writes a non-API object in JSON.
-func WriteRawJSON(statusCode int, object interface{}, w http.ResponseWriter) {
-	output, err := json.MarshalIndent(object, "", "  ")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	w.Write(output)
-}
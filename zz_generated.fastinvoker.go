@@ -0,0 +1,91 @@
+// Code generated by fastinvoker-gen. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/tamalsaha/learn-chi/binding"
+	"go.wandrs.dev/inject"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// fastInvoker0 calls handlers of type func(*binding.Items) ([]string, error) without reflection.
+type fastInvoker0 func(*binding.Items) ([]string, error)
+
+func (f fastInvoker0) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(*binding.Items)
+	r0, r1 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem(), reflect.ValueOf(&r1).Elem()}, nil
+}
+
+// fastInvoker1 calls handlers of type func(Greeting) string without reflection.
+type fastInvoker1 func(Greeting) string
+
+func (f fastInvoker1) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(Greeting)
+	r0 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem()}, nil
+}
+
+// fastInvoker2 calls handlers of type func(HelloParams) string without reflection.
+type fastInvoker2 func(HelloParams) string
+
+func (f fastInvoker2) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(HelloParams)
+	r0 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem()}, nil
+}
+
+// fastInvoker3 calls handlers of type func(Upload) string without reflection.
+type fastInvoker3 func(Upload) string
+
+func (f fastInvoker3) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(Upload)
+	r0 := f(a0)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem()}, nil
+}
+
+// fastInvoker4 calls handlers of type func(context.Context, kubernetes.Interface, PodParams) (*core.PodList, error) without reflection.
+type fastInvoker4 func(context.Context, kubernetes.Interface, PodParams) (*core.PodList, error)
+
+func (f fastInvoker4) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(context.Context)
+	a1, _ := args[1].(kubernetes.Interface)
+	a2, _ := args[2].(PodParams)
+	r0, r1 := f(a0, a1, a2)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem(), reflect.ValueOf(&r1).Elem()}, nil
+}
+
+// fastInvoker5 calls handlers of type func(kubernetes.Interface, User) ([]byte, error) without reflection.
+type fastInvoker5 func(kubernetes.Interface, User) ([]byte, error)
+
+func (f fastInvoker5) Invoke(args []interface{}) ([]reflect.Value, error) {
+	a0, _ := args[0].(kubernetes.Interface)
+	a1, _ := args[1].(User)
+	r0, r1 := f(a0, a1)
+	return []reflect.Value{reflect.ValueOf(&r0).Elem(), reflect.ValueOf(&r1).Elem()}, nil
+}
+
+func init() {
+	binding.RegisterFastInvoker(reflect.TypeOf((func(*binding.Items) ([]string, error))(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker0(fn.(func(*binding.Items) ([]string, error)))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(Greeting) string)(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker1(fn.(func(Greeting) string))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(HelloParams) string)(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker2(fn.(func(HelloParams) string))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(Upload) string)(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker3(fn.(func(Upload) string))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(context.Context, kubernetes.Interface, PodParams) (*core.PodList, error))(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker4(fn.(func(context.Context, kubernetes.Interface, PodParams) (*core.PodList, error)))
+	})
+	binding.RegisterFastInvoker(reflect.TypeOf((func(kubernetes.Interface, User) ([]byte, error))(nil)), func(fn interface{}) inject.FastInvoker {
+		return fastInvoker5(fn.(func(kubernetes.Interface, User) ([]byte, error)))
+	})
+}